@@ -0,0 +1,51 @@
+package main
+
+import "strings"
+
+// lineEndOptions управляет обработкой концов строк результата
+type lineEndOptions struct {
+	showEnds     bool // отмечать конец каждой строки символом $, как `cat -e`
+	trimTrailing bool // удалять хвостовые пробелы в каждой строке
+}
+
+// applyLineEnds применяет настройки концов строк к готовому ASCII-арту.
+// С --show-ends ширина каждой строки глифов видна явно, включая хвостовые пробелы.
+func applyLineEnds(text string, opts lineEndOptions) string {
+	if !opts.showEnds && !opts.trimTrailing {
+		return text
+	}
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		last := i == len(lines)-1 // после последнего \n может остаться только хвост без переноса
+		if last && line == "" {
+			break
+		}
+		if opts.trimTrailing {
+			line = trimTrailing(line)
+		}
+		if opts.showEnds && !last {
+			line += "$"
+		}
+		lines[i] = line
+	}
+	return strings.Join(lines, "\n")
+}
+
+// trimTrailing удаляет хвостовые пробелы строки, не теряя ANSI-кодов:
+// пробелы перед завершающим reset тоже считаются хвостовыми
+func trimTrailing(line string) string {
+	var codes []string // escape-последовательности, снятые с конца строки
+	for {
+		line = strings.TrimRight(line, " \t")
+		if !strings.HasSuffix(line, "m") {
+			break
+		}
+		start := strings.LastIndex(line, "\033[")
+		if start == -1 || strings.Trim(line[start+2:len(line)-1], "0123456789;") != "" {
+			break
+		}
+		codes = append([]string{line[start:]}, codes...)
+		line = line[:start]
+	}
+	return line + strings.Join(codes, "")
+}
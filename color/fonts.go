@@ -0,0 +1,46 @@
+package main
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// bundledFonts — шрифты, которые лежат рядом с программой
+var bundledFonts = []string{"standard", "shadow", "thinkertoy"}
+
+// fontInfo — найденный шрифт: имя для --banner и путь к файлу
+type fontInfo struct {
+	name string
+	path string
+}
+
+// discoverFonts находит шрифты, доступные по имени: файлы *.txt из каталогов
+// font-path и встроенные шрифты. Шрифт с одним именем берётся из первого каталога,
+// как и при выборе --banner; файлы, которые не разбираются как шрифт, пропускаются.
+func discoverFonts(fontPath string) []fontInfo {
+	var paths []string
+	for _, dir := range filepath.SplitList(fontPath) {
+		if dir == "" {
+			continue
+		}
+		matches, _ := filepath.Glob(filepath.Join(dir, "*.txt"))
+		paths = append(paths, matches...)
+	}
+	for _, name := range bundledFonts {
+		paths = append(paths, name+".txt")
+	}
+
+	var fonts []fontInfo
+	seen := make(map[string]bool)
+	for _, path := range paths {
+		name := strings.TrimSuffix(filepath.Base(path), ".txt")
+		if seen[name] || NewASCIIArt().LoadFont(path) != nil {
+			continue
+		}
+		seen[name] = true
+		fonts = append(fonts, fontInfo{name: name, path: path})
+	}
+	sort.Slice(fonts, func(i, j int) bool { return fonts[i].name < fonts[j].name })
+	return fonts
+}
@@ -3,7 +3,9 @@ package main
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"os"
+	"sort"
 	"strings"
 )
 
@@ -37,6 +39,7 @@ type ASCIIChar struct {
 // ASCIIArt хранит карту символов и их ASCII-представления
 type ASCIIArt struct {
 	chars map[rune]ASCIIChar
+	width int // ширина переноса строк в колонках, 0 — без переноса
 }
 
 // NewASCIIArt создаёт и возвращает новый экземпляр структуры ASCIIArt
@@ -46,73 +49,103 @@ func NewASCIIArt() *ASCIIArt {
 	}
 }
 
+// colorCodes связывает названия цветов с их ANSI-кодами
+var colorCodes = map[string]string{
+	"red":    red,
+	"orange": orange,
+	"yellow": yellow,
+	"green":  green,
+	"blue":   blue,
+	"indigo": indigo,
+	"violet": violet,
+	"purple": purple,
+	"cyan":   cyan,
+	"white":  white,
+}
+
 // getColorCode возвращает соответствующий ANSI-код для этого цвета
 func getColorCode(color string) string {
-	colorMap := map[string]string{
-		"red":    red,
-		"orange": orange,
-		"yellow": yellow,
-		"green":  green,
-		"blue":   blue,
-		"indigo": indigo,
-		"violet": violet,
-		"purple": purple,
-		"cyan":   cyan,
-		"white":  white,
-	}
-	if code, exists := colorMap[strings.ToLower(color)]; exists {
+	if code, exists := colorCodes[strings.ToLower(color)]; exists {
 		return code
 	}
 	return white // цвет по умолчанию
 }
 
-// parseArgs парсинг аргументов командной строки
-func parseArgs(args []string) (ColorConfig, string, string, error) {
-	var colorConfig ColorConfig
-	var text, banner string
-
-	if len(args) < 2 {
-		return colorConfig, "", "", fmt.Errorf("insufficient arguments")
-	}
-
-	// Проверка наличия опции цвета
-	if strings.HasPrefix(args[1], "--color=") {
-		colorConfig.enabled = true
-		colorConfig.color = strings.TrimPrefix(args[1], "--color=")
-
-		if len(args) == 3 {
-			// Пример: go run . --color=blue "hello"
-			text = args[2]
-			banner = "standard" // баннер по умолчанию
-		} else if len(args) == 4 {
-			if strings.HasSuffix(args[3], ".txt") || args[3] == "standard" || args[3] == "shadow" || args[3] == "thinkertoy" {
-				// Пример: go run . --color=green "hello" thinkertoy
-				text = args[2]
-				banner = args[3]
-			} else {
-				// Пример: go run . --color=red kit "a king kitten have kit"
-				colorConfig.substring = args[2]
-				text = args[3]
-				banner = "standard" // Default banner
-			}
-		} else if len(args) == 5 {
-			// Пример: go run . --color=red h "hello" standard
-			colorConfig.substring = args[2]
-			text = args[3]
-			banner = args[4]
-		} else {
-			return colorConfig, "", "", fmt.Errorf("invalid number of arguments")
-		}
-	} else {
-		// Пример: go run . "hello" standard
-		text = args[1]
-		if len(args) >= 3 {
-			banner = args[2]
-		} else {
-			banner = "standard" // баннер по умолчанию
+// colorNames возвращает названия цветов по алфавиту
+func colorNames() []string {
+	names := make([]string, 0, len(colorCodes))
+	for name := range colorCodes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// isValidColor проверяет, известен ли цвет (без учёта регистра)
+func isValidColor(color string) bool {
+	_, exists := colorCodes[strings.ToLower(color)]
+	return exists
+}
+
+// parseArgs разбирает позиционные аргументы, оставшиеся после флагов.
+// С --color: СТРОКА, ПОДСТРОКА СТРОКА или ПОДСТРОКА СТРОКА БАННЕР;
+// без него: СТРОКА или СТРОКА БАННЕР. Баннер можно указать и флагом --banner.
+// Цвет из настроек не меняет смысл аргументов: он окрашивает весь текст.
+func parseArgs(args []string, opts cliOptions) (ColorConfig, string, string, error) {
+	colorConfig := opts.colorConfig
+	banner := opts.banner
+
+	// Текст из файла или stdin занимает место СТРОКИ среди аргументов
+	input := opts.input
+	if input == "" && len(args) == 0 && stdinIsPiped() {
+		input = stdinName
+	}
+	if input != "" {
+		pos := 0
+		if colorConfig.enabled && len(args) > 0 {
+			pos = 1 // после ПОДСТРОКИ
 		}
+		args = append(args[:pos:pos], append([]string{stdinName}, args[pos:]...)...)
+	}
+	if input == "" {
+		input = stdinName // аргумент "-" читает stdin
+	}
+
+	maxArgs := 2
+	if colorConfig.enabled {
+		maxArgs = 3
+	}
+	if len(args) == 0 {
+		return colorConfig, "", "", withExitCode(exitUsage, errorf("missing text"))
+	}
+	if len(args) > maxArgs {
+		return colorConfig, "", "", withExitCode(exitUsage, errorf("unexpected argument %q", args[maxArgs]))
 	}
 
+	// Пример: go run . --color=red kit "a king kitten have kit"
+	if colorConfig.enabled && len(args) >= 2 {
+		colorConfig.substring = args[0]
+		args = args[1:]
+	}
+	text := expandNewlines(args[0])
+	if args[0] == stdinName {
+		var err error
+		if text, err = readInput(input); err != nil {
+			return colorConfig, "", "", withExitCode(exitIO, err)
+		}
+	}
+	if len(args) == 2 {
+		if banner != "" {
+			return colorConfig, "", "", withExitCode(exitUsage, errorf("banner given twice: --banner=%s and %q", banner, args[1]))
+		}
+		banner = args[1]
+	}
+	if banner == "" {
+		banner = opts.defaultBanner
+	}
+	if !colorConfig.enabled {
+		colorConfig = opts.defaultColor
+	}
 	return colorConfig, text, banner, nil
 }
 
@@ -120,7 +153,7 @@ func parseArgs(args []string) (ColorConfig, string, string, error) {
 func (a *ASCIIArt) LoadFont(filename string) error {
 	file, err := os.Open(filename)
 	if err != nil {
-		return fmt.Errorf("failed to open %s: %v", filename, err)
+		return errorf("failed to open %s: %w", filename, err)
 	}
 	defer file.Close()
 
@@ -148,6 +181,9 @@ func (a *ASCIIArt) LoadFont(filename string) error {
 		if line == "" {
 			// Если символ уже полностью прочитан, сохраняем его в map
 			if lineIndex > 0 && charIndex < len(supportedChars) {
+				if lineIndex < 8 {
+					return errorf("%w %s: character %q has %d lines instead of 8", errInvalidFont, filename, supportedChars[charIndex], lineIndex)
+				}
 				a.chars[supportedChars[charIndex]] = ASCIIChar{lines: currentLines}
 				charIndex++
 				lineIndex = 0
@@ -158,13 +194,26 @@ func (a *ASCIIArt) LoadFont(filename string) error {
 			if lineIndex < 8 {
 				currentLines[lineIndex] = line
 				lineIndex++
+			} else if charIndex < len(supportedChars) {
+				return errorf("%w %s: character %q has more than 8 lines", errInvalidFont, filename, supportedChars[charIndex])
 			}
 		}
 	}
 
 	// Добавляем последний символ, если файл не завершен пустой строкой
 	if lineIndex > 0 && charIndex < len(supportedChars) {
+		if lineIndex < 8 {
+			return errorf("%w %s: character %q has %d lines instead of 8", errInvalidFont, filename, supportedChars[charIndex], lineIndex)
+		}
 		a.chars[supportedChars[charIndex]] = ASCIIChar{lines: currentLines}
+		charIndex++
+	}
+	if err := scanner.Err(); err != nil {
+		return errorf("failed to read %s: %w", filename, err)
+	}
+	// Шрифт должен содержать все символы от пробела до тильды
+	if charIndex < len(supportedChars) {
+		return errorf("%w %s: %d of %d characters", errInvalidFont, filename, charIndex, len(supportedChars))
 	}
 
 	return nil
@@ -179,13 +228,13 @@ func (a *ASCIIArt) RenderText(input string, colorConfig ColorConfig) string {
 		return ""
 	}
 
-	// Если введен специальный символ для новой строки, заменяем его на стандартный символ новой строки
-	if input == "\\n" {
+	// Если введен только перевод строки, возвращаем стандартный символ новой строки
+	if input == "\n" {
 		return "\n"
 	}
 
-	// Разбиваем входной текст на строки, разделенные символами \n
-	lines := strings.Split(input, "\\n")
+	// Разбиваем входной текст на строки; \n из аргумента уже заменён переводом строки
+	lines := strings.Split(input, "\n")
 	colorCode := getColorCode(colorConfig.color)
 
 	for lineNum, line := range lines {
@@ -194,8 +243,6 @@ func (a *ASCIIArt) RenderText(input string, colorConfig ColorConfig) string {
 			continue
 		}
 
-		// Массив для хранения 8 строк ASCII-арта для каждой строки текста
-		var artLines [8]strings.Builder
 		var colorPositions []int
 
 		// Если цветная подсветка включена и подстрока задана, ищем все позиции для окраски
@@ -211,40 +258,47 @@ func (a *ASCIIArt) RenderText(input string, colorConfig ColorConfig) string {
 			}
 		}
 
-		for charIdx, char := range line {
-			// Проверяем, есть ли ASCII-арт для текущего символа
-			if art, exists := a.chars[char]; exists {
-				// Проверяем, нужно ли применить цвет для этого символа
-				shouldColor := colorConfig.enabled && (colorConfig.substring == "" ||
-					(func() bool {
-						// Проверяем, попадает ли текущий символ в область подсветки
-						for _, pos := range colorPositions {
-							if charIdx >= pos && charIdx < pos+len(colorConfig.substring) {
-								return true
+		// Позиции подсветки ищем по всей строке, чтобы перенос не разрывал подстроку;
+		// затем переносим строку по словам, если её ASCII-арт не помещается в ширину
+		for _, part := range a.wrapLine(line, a.width) {
+			// Массив для хранения 8 строк ASCII-арта для каждой части строки
+			var artLines [8]strings.Builder
+			for charIdx, char := range line[part.start:part.end] {
+				charIdx += part.start // позиция символа в исходной строке
+				// Проверяем, есть ли ASCII-арт для текущего символа
+				if art, exists := a.chars[char]; exists {
+					// Проверяем, нужно ли применить цвет для этого символа
+					shouldColor := colorConfig.enabled && (colorConfig.substring == "" ||
+						(func() bool {
+							// Проверяем, попадает ли текущий символ в область подсветки
+							for _, pos := range colorPositions {
+								if charIdx >= pos && charIdx < pos+len(colorConfig.substring) {
+									return true
+								}
 							}
+							return false
+						})())
+
+					// Добавляем все строки символа в финальный результат
+					for lineIdx := 0; lineIdx < 8; lineIdx++ {
+						if shouldColor {
+							artLines[lineIdx].WriteString(colorCode + art.lines[lineIdx] + reset)
+						} else {
+							artLines[lineIdx].WriteString(art.lines[lineIdx])
 						}
-						return false
-					})())
-
-				// Добавляем все строки символа в финальный результат
-				for lineIdx := 0; lineIdx < 8; lineIdx++ {
-					if shouldColor {
-						artLines[lineIdx].WriteString(colorCode + art.lines[lineIdx] + reset)
-					} else {
-						artLines[lineIdx].WriteString(art.lines[lineIdx])
 					}
 				}
 			}
-		}
 
-		// Если в конце строки имеется специальный символ для новой строки, добавляем пустую строку
-		for lineIdx := 0; lineIdx < 8; lineIdx++ {
-			result.WriteString(artLines[lineIdx].String())
-			result.WriteString("\n")
+			// Если в конце строки имеется специальный символ для новой строки, добавляем пустую строку
+			for lineIdx := 0; lineIdx < 8; lineIdx++ {
+				result.WriteString(artLines[lineIdx].String())
+				result.WriteString("\n")
+			}
 		}
 
 		// Возвращаем сгенерированный ASCII-арт
-		if lineNum < len(lines)-1 || (len(input) >= 2 && input[len(input)-2:] == "\\n") {
+		if lineNum < len(lines)-1 || strings.HasSuffix(input, "\n") {
 			result.WriteString("")
 		}
 	}
@@ -252,49 +306,239 @@ func (a *ASCIIArt) RenderText(input string, colorConfig ColorConfig) string {
 	return result.String()
 }
 
-func printUsage() {
-	fmt.Println("Usage:")
-	fmt.Println("  1. go run . [STRING] [BANNER]")
-	fmt.Println("  2. go run . [OPTION] [STRING]")
-	fmt.Println("  3. go run . [OPTION] [STRING] [BANNER]")
-	fmt.Println("\nExamples:")
-	fmt.Println("  go run . \"hello\" standard")
-	fmt.Println("  go run . --color=red kit \"a king kitten have kit\"")
-	fmt.Println("  go run . --color=red h \"hello\" standard")
-	fmt.Println("  go run . --color=green \"hello\" thinkertoy")
+// usageSynopses — варианты вызова программы для справки и man-страницы
+var usageSynopses = []string{
+	"[OPTION]... [STRING] [BANNER]",
+	"--color=COLOR [OPTION]... [SUBSTRING] [STRING] [BANNER]",
 }
 
-func main() {
-	if len(os.Args) < 2 {
-		printUsage()
-		return
+// usageColumn — колонка, с которой в справке начинаются описания флагов
+const usageColumn = 21
+
+// printUsage выводит инструкцию по использованию в w
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, tr("Usage:"))
+	for i, synopsis := range usageSynopses {
+		fmt.Fprintf(w, "  %d. go run . %s\n", i+1, synopsis)
 	}
+	fmt.Fprintln(w, "\n"+tr("Options (in any order, --flag=value or --flag value; -- ends options):"))
+	printFlags(w, (&cliOptions{}).flagSpecs(), usageColumn)
+	fmt.Fprintln(w, "\n"+tr("Examples:"))
+	fmt.Fprintln(w, "  go run . \"hello\" standard")
+	fmt.Fprintln(w, "  go run . --color=red kit \"a king kitten have kit\"")
+	fmt.Fprintln(w, "  go run . --color=red h \"hello\" standard")
+	fmt.Fprintln(w, "  go run . --color green -b thinkertoy \"hello\"")
+	fmt.Fprintln(w, "  go run . -c blue -- -dash-")
+	fmt.Fprintln(w, "  git describe | go run . --color=red v -")
+	fmt.Fprintln(w, "\n"+tr("Defaults for banner, color, width, font-path, unsupported and placeholder are read\nfrom $XDG_CONFIG_HOME/ascii-art/config, .asciiartrc and ASCII_ART_* variables."))
+}
 
-	colorConfig, text, bannerType, err := parseArgs(os.Args)
+func main() {
+	// Язык сообщений выбирается до разбора флагов (см. messages.go)
+	lang = detectLang(os.Args[1:])
+	// Флаги могут стоять в любом месте командной строки.
+	// Ошибки выводятся в stderr, код завершения зависит от класса ошибки (см. exit.go)
+	opts, args, err := parseOptions(os.Args[1:])
 	if err != nil {
-		printUsage()
+		usageError(err)
+	}
+	if opts.help {
+		printUsage(os.Stdout)
 		return
 	}
-
-	var fontFile string
-	switch bannerType {
-	case "standard", "standard.txt":
-		fontFile = "standard.txt"
-	case "shadow", "shadow.txt":
-		fontFile = "shadow.txt"
-	case "thinkertoy", "thinkertoy.txt":
-		fontFile = "thinkertoy.txt"
-	default:
-		fmt.Printf("Error: Unknown font type '%s'. Supported types are: standard, shadow, thinkertoy.\n", bannerType)
+	if opts.configErr != nil {
+		usageError(opts.configErr)
+	}
+	// config show — то же, что --show-config
+	if opts.action == "" && isConfigShow(os.Args[1:], args) {
+		opts.action, args = "show-config", nil
+	}
+	// Флаги действий выполняются вместо рендеринга и не принимают текста
+	if opts.action != "" {
+		if len(args) > 0 {
+			usageError(errorf("unexpected argument %q", args[0]))
+		}
+		switch opts.action {
+		case "show-config":
+			showConfig(os.Stdout, opts.settings)
+		case "completion":
+			writeCompletion(os.Stdout, opts.shell, programName(), (&cliOptions{}).flagSpecs())
+		case "man":
+			writeManPage(os.Stdout, programName(), (&cliOptions{}).flagSpecs())
+		case "list-fonts":
+			for _, font := range discoverFonts(opts.fontPath) {
+				fmt.Println(font.name)
+			}
+		}
 		return
 	}
+	colorConfig, text, bannerType, err := parseArgs(args, opts)
+	if err != nil {
+		if exitCodeFor(err) != exitUsage {
+			fail(exitCodeFor(err), "%v", err)
+		}
+		usageError(err)
+	}
+
+	// Шрифт ищется сначала в каталогах --font-path, где могут лежать и другие шрифты
+	bannerType = strings.TrimSuffix(bannerType, ".txt")
+	fontFile := resolveFont(bannerType+".txt", opts.fontPath)
+	switch {
+	case bannerType == "standard", bannerType == "shadow", bannerType == "thinkertoy":
+	case fontFile == bannerType+".txt" || strings.ContainsAny(bannerType, `/\`):
+		fail(exitFontMissing, "Unknown font type '%s'. Supported types are: standard, shadow, thinkertoy.", bannerType)
+	}
 
 	ascii := NewASCIIArt()
 	if err := ascii.LoadFont(fontFile); err != nil {
-		fmt.Printf("Error loading font file '%s': %v\n", fontFile, err)
-		return
+		fail(fontExitCode(err), "loading font file '%s': %v", fontFile, err)
+	}
+
+	// Символы, которых нет в шрифте, обрабатываем по политике --unsupported;
+	// подстроку обрабатываем так же, чтобы она совпадала с текстом
+	if text, err = ascii.applyCharPolicy(text, opts.chars); err != nil {
+		fail(exitUnsupported, "%v", err)
+	}
+	if colorConfig.substring, err = ascii.applyCharPolicy(colorConfig.substring, opts.chars); err != nil {
+		fail(exitUnsupported, "substring: %v", err)
 	}
 
-	output := ascii.RenderText(text, colorConfig)
-	fmt.Print(output)
+	ascii.width = wrapWidth(opts.width)
+	output := applyLineEnds(ascii.RenderText(text, colorConfig), opts.ends)
+	if _, err := fmt.Print(output); err != nil {
+		fail(exitIO, "writing output: %v", err)
+	}
+}
+
+// usageError выводит ошибку и справку в stderr и завершает программу с кодом exitUsage
+func usageError(err error) {
+	fmt.Fprintln(os.Stderr, tr("Error:"), err)
+	printUsage(os.Stderr)
+	os.Exit(exitUsage)
+}
+
+// cliOptions хранит разобранные флаги командной строки
+type cliOptions struct {
+	ends          lineEndOptions         // отметка концов строк и удаление хвостовых пробелов
+	width         int                    // ширина переноса: 0 — по терминалу, -1 — без переноса
+	colorConfig   ColorConfig            // цвет из --color
+	banner        string                 // баннер из --banner, пусто — не задан
+	input         string                 // файл с текстом из --input, "-" — стандартный ввод
+	fontPath      string                 // каталоги для поиска шрифтов через разделитель списка путей
+	defaultBanner string                 // баннер из настроек, если его не задали аргументом или флагом
+	defaultColor  ColorConfig            // цвет из настроек: окрашивает весь текст, если нет --color
+	settings      map[string]configEntry // действующие настройки для --show-config
+	configErr     error                  // ошибка в файлах настроек или ASCII_ART_*
+	chars         charPolicy             // обработка символов, которых нет в шрифте
+	noWrap        bool                   // не переносить длинные строки
+	action        string                 // флаг, который выполняется вместо рендеринга, например --man
+	shell         string                 // оболочка для --completion
+	help          bool
+}
+
+// flagSpecs описывает флаги программы; обработчики сохраняют значения в opts
+func (opts *cliOptions) flagSpecs() []flagSpec {
+	policies := []string{policySkip, policyError, policyPlaceholder, policyTranslit}
+	return []flagSpec{
+		{name: "color", short: "c", hasValue: true, arg: "COLOR", values: colorNames(),
+			usage: "color the substring, or the whole text without one",
+			set: func(value string) error {
+				if !isValidColor(value) {
+					return errorf("unknown color")
+				}
+				opts.colorConfig = ColorConfig{color: value, enabled: true}
+				return nil
+			}},
+		{name: "banner", short: "b", hasValue: true, arg: "NAME", complete: completeFont,
+			usage: "banner: standard, shadow, thinkertoy or a font from the font path",
+			set: func(value string) error {
+				opts.banner = value
+				return nil
+			}},
+		{name: "input", short: "i", hasValue: true, arg: "FILE", complete: completeFile,
+			usage: "read STRING from FILE ('-' is stdin); STRING can also be '-',\nand is read from stdin when omitted and input is piped",
+			set: func(value string) error {
+				if value == "" {
+					return errorf("missing file name")
+				}
+				opts.input = value
+				return nil
+			}},
+		{name: "width", short: "w", hasValue: true, arg: "N",
+			usage: "wrap long text to N columns (default: terminal width)", set: setInt(&opts.width, 1)},
+		{name: "no-wrap", usage: "do not wrap long text", set: setTrue(&opts.noWrap)},
+		{name: "unsupported", short: "u", hasValue: true, arg: "MODE", values: policies,
+			usage: "characters missing from the font: skip (default), error,\nplaceholder or translit (é→e, ß→ss, curly quotes→straight)",
+			set:   setOneOf(&opts.chars.mode, policies...)},
+		{name: "placeholder", hasValue: true, arg: "CHAR",
+			usage: "draw CHAR (or 'box') for missing characters (default '?')",
+			set: func(value string) error {
+				opts.chars.mode = policyPlaceholder
+				opts.chars.placeholder = value
+				return validatePlaceholder(value)
+			}},
+		{name: "font-path", hasValue: true, arg: "DIRS", complete: completeDir,
+			usage: "directories searched for banner files first (':'-separated)",
+			set: func(value string) error {
+				opts.fontPath = value
+				return nil
+			}},
+		{name: "show-ends", short: "E", usage: "mark every line end with $", set: setTrue(&opts.ends.showEnds)},
+		{name: "trim-trailing", short: "T", usage: "strip trailing spaces", set: setTrue(&opts.ends.trimTrailing)},
+		{name: "show-config", usage: "print the effective settings and where they come from",
+			set: setAction(&opts.action, "show-config")},
+		{name: "completion", hasValue: true, arg: "SHELL", values: completionShells,
+			usage: "print a completion script for bash, zsh or fish",
+			set: func(value string) error {
+				if err := setOneOf(&opts.shell, completionShells...)(value); err != nil {
+					return err
+				}
+				return setAction(&opts.action, "completion")(value)
+			}},
+		{name: "man", usage: "print the manual page in roff format", set: setAction(&opts.action, "man")},
+		{name: "list-fonts", usage: "list the fonts found in the font path and the bundled ones",
+			set: setAction(&opts.action, "list-fonts")},
+		{name: "lang", hasValue: true, arg: "LANG", values: languages,
+			usage: "language of help and messages: en or ru (default: from LC_ALL or LANG)",
+			set:   setOneOf(&lang, languages...)},
+		{name: "help", short: "h", usage: "show this help", set: setTrue(&opts.help)},
+	}
+}
+
+// parseOptions разбирает флаги и возвращает их вместе с позиционными аргументами
+func parseOptions(args []string) (cliOptions, []string, error) {
+	opts := cliOptions{chars: charPolicy{mode: policySkip, placeholder: "?"}}
+	specs := opts.flagSpecs()
+
+	// Настройки применяются от младших к старшим: встроенные, файл пользователя,
+	// файл проекта, переменные окружения и, наконец, флаги командной строки
+	opts.settings = map[string]configEntry{
+		"banner":      {"standard", "built-in"},
+		"color":       {"", "built-in"},
+		"width":       {"terminal", "built-in"},
+		"font-path":   {"", "built-in"},
+		"unsupported": {policySkip, "built-in"},
+		"placeholder": {"?", "built-in"},
+	}
+	layers, err := configLayers()
+	opts.banner = "standard"
+	if err == nil {
+		err = applyConfig(specs, layers, opts.settings)
+	}
+	// Ошибку в настройках сообщает main после --help, чтобы справка
+	// открывалась и при испорченном .asciiartrc
+	opts.configErr = err
+	opts.defaultBanner, opts.banner = opts.banner, ""
+	opts.defaultColor, opts.colorConfig = opts.colorConfig, ColorConfig{}
+
+	trackFlags(specs, opts.settings)
+	positional, err := parseFlags(args, specs)
+	if opts.chars.mode != opts.settings["unsupported"].value {
+		// --placeholder включает режим placeholder
+		opts.settings["unsupported"] = configEntry{opts.chars.mode, opts.settings["placeholder"].source}
+	}
+	if opts.noWrap {
+		opts.width = -1
+	}
+	return opts, positional, err
 }
@@ -0,0 +1,21 @@
+package main
+
+import (
+	"os"
+	"strconv"
+)
+
+// wrapWidth возвращает ширину, по которой переносится текст: явное значение
+// --width, затем ширина терминала на stdout, затем $COLUMNS; 0 — не переносить
+func wrapWidth(override int) int {
+	if override != 0 {
+		return max(override, 0)
+	}
+	if width, ok := terminalWidth(os.Stdout.Fd()); ok {
+		return width
+	}
+	if columns, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && columns > 0 {
+		return columns
+	}
+	return 0
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Политики обработки символов, которых нет в шрифте (--unsupported)
+const (
+	policySkip        = "skip"        // пропустить символ (по умолчанию)
+	policyError       = "error"       // завершиться с ошибкой и перечислить символы
+	policyPlaceholder = "placeholder" // нарисовать вместо символа заглушку (--placeholder)
+	policyTranslit    = "translit"    // заменить похожими символами ASCII: é→e, ß→ss, “→"
+)
+
+// boxPlaceholder — значение --placeholder, рисующее вместо символа рамку
+const boxPlaceholder = "box"
+
+// boxRune — руна, под которой глиф-рамка добавляется в шрифт
+const boxRune = '\uFFFD'
+
+// errUnsupported оборачивает ошибку о символах, которых нет в шрифте
+var errUnsupported = message("unsupported characters")
+
+// charPolicy описывает, что делать с символами, которых нет в шрифте
+type charPolicy struct {
+	mode        string
+	placeholder string // символ шрифта или "box"
+}
+
+// validatePlaceholder проверяет значение --placeholder: один символ или "box"
+func validatePlaceholder(value string) error {
+	if value != boxPlaceholder && utf8.RuneCountInString(value) != 1 {
+		return errorf("must be a single character or %q", boxPlaceholder)
+	}
+	return nil
+}
+
+// translitLower — транслитерация строчных букв; для заглавных замена
+// получается из строчной (Ж→Zh, É→E)
+var translitLower = map[rune]string{
+	'à': "a", 'á': "a", 'â': "a", 'ã': "a", 'ä': "a", 'å': "a", 'ā': "a", 'ă': "a", 'ą': "a",
+	'æ': "ae", 'ç': "c", 'ć': "c", 'č': "c", 'ď': "d", 'đ': "d", 'ð': "d",
+	'è': "e", 'é': "e", 'ê': "e", 'ë': "e", 'ē': "e", 'ę': "e", 'ě': "e", 'ğ': "g",
+	'ì': "i", 'í': "i", 'î': "i", 'ï': "i", 'ī': "i", 'ı': "i", 'ł': "l", 'ľ': "l",
+	'ñ': "n", 'ń': "n", 'ň': "n", 'ò': "o", 'ó': "o", 'ô': "o", 'õ': "o", 'ö': "o", 'ø': "o", 'ő': "o",
+	'œ': "oe", 'ŕ': "r", 'ř': "r", 'ß': "ss", 'ś': "s", 'š': "s", 'ş': "s", 'ť': "t", 'ţ': "t", 'þ': "th",
+	'ù': "u", 'ú': "u", 'û': "u", 'ü': "u", 'ū': "u", 'ů': "u", 'ű': "u",
+	'ý': "y", 'ÿ': "y", 'ź': "z", 'ż': "z", 'ž': "z",
+	// Русский алфавит, упрощённая транслитерация
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "e", 'ж': "zh",
+	'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o",
+	'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "kh", 'ц': "ts",
+	'ч': "ch", 'ш': "sh", 'щ': "shch", 'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu", 'я': "ya",
+}
+
+// translitSymbols — транслитерация кавычек, тире и прочих знаков
+var translitSymbols = map[rune]string{
+	'‘': "'", '’': "'", '‚': "'", '‛': "'", '′': "'", '‹': "'", '›': "'",
+	'“': "\"", '”': "\"", '„': "\"", '‟': "\"", '″': "\"", '«': "\"", '»': "\"",
+	'‐': "-", '‑': "-", '‒': "-", '–': "-", '—': "-", '―': "-", '−': "-",
+	'…': "...", '•': "*", '·': ".", '×': "x", '÷': "/", '\u00A0': " ", '\t': " ",
+	'©': "(c)", '®': "(R)", '™': "TM", '€': "EUR", '№': "No",
+}
+
+// transliterate возвращает замену из символов ASCII для r
+func transliterate(r rune) (string, bool) {
+	if s, ok := translitSymbols[r]; ok {
+		return s, true
+	}
+	if s, ok := translitLower[r]; ok {
+		return s, true
+	}
+	if lower := unicode.ToLower(r); lower != r {
+		if s, ok := translitLower[lower]; ok {
+			if s == "" {
+				return s, true
+			}
+			return strings.ToUpper(s[:1]) + s[1:], true
+		}
+	}
+	return "", false
+}
+
+// applyCharPolicy готовит текст к рендеру: символы, которых нет в шрифте,
+// пропускаются, заменяются заглушкой или транслитерируются. С политикой error
+// (и для символов, которые не удалось транслитерировать) возвращается ошибка
+// со списком символов и их позиций (строка:колонка).
+func (a *ASCIIArt) applyCharPolicy(input string, policy charPolicy) (string, error) {
+	placeholder := ""
+	if policy.mode == policyPlaceholder {
+		if policy.placeholder == boxPlaceholder {
+			a.addBoxGlyph()
+			placeholder = string(boxRune)
+		} else if _, ok := a.chars[[]rune(policy.placeholder)[0]]; ok {
+			placeholder = policy.placeholder
+		} else {
+			return "", errorf("%w: placeholder %q is not in the font", errUnsupported, policy.placeholder)
+		}
+	}
+
+	var b strings.Builder
+	var bad []string
+	line, col := 1, 0
+	for _, r := range input {
+		// Разрывы строк: \n из аргумента уже заменён переводом строки
+		if r == '\n' {
+			b.WriteRune(r)
+			line, col = line+1, 0
+			continue
+		}
+		col++
+		if _, ok := a.chars[r]; ok {
+			b.WriteRune(r)
+			continue
+		}
+		switch policy.mode {
+		case policySkip:
+		case policyPlaceholder:
+			b.WriteString(placeholder)
+		case policyTranslit:
+			if s, ok := transliterate(r); ok && a.supports(s) {
+				b.WriteString(s)
+				continue
+			}
+			bad = append(bad, fmt.Sprintf(tr("%q (%U) at %d:%d"), r, r, line, col))
+		default:
+			bad = append(bad, fmt.Sprintf(tr("%q (%U) at %d:%d"), r, r, line, col))
+		}
+	}
+	if len(bad) > 0 {
+		return "", errorf("%w: %s", errUnsupported, strings.Join(bad, ", "))
+	}
+	return b.String(), nil
+}
+
+// supports сообщает, есть ли в шрифте все символы строки
+func (a *ASCIIArt) supports(s string) bool {
+	for _, r := range s {
+		if _, ok := a.chars[r]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// addBoxGlyph добавляет в шрифт глиф-рамку шириной с символ '?'
+func (a *ASCIIArt) addBoxGlyph() {
+	width := 4
+	for _, line := range a.chars['?'].lines {
+		width = max(width, displayWidth(line))
+	}
+	inner := width - 3 // рамка, внутренность и пробел после глифа
+	var glyph ASCIIChar
+	for i := range glyph.lines {
+		switch i {
+		case 0, len(glyph.lines) - 1:
+			glyph.lines[i] = "+" + strings.Repeat("-", inner) + "+ "
+		default:
+			glyph.lines[i] = "|" + strings.Repeat(" ", inner) + "| "
+		}
+	}
+	a.chars[boxRune] = glyph
+}
@@ -0,0 +1,234 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// configKeys — ключи файла настроек. Файл общий для всех программ проекта:
+// каждая применяет только ключи, для которых у неё есть одноимённый флаг,
+// а остальные пропускает.
+var configKeys = []string{"banner", "color", "align", "width", "format", "font-path", "unsupported", "placeholder"}
+
+// configEnvPrefix — префикс переменных окружения: ASCII_ART_BANNER, ASCII_ART_FONT_PATH и т.д.
+const configEnvPrefix = "ASCII_ART_"
+
+// projectConfigName — файл настроек проекта, ищется в текущем каталоге и выше
+const projectConfigName = ".asciiartrc"
+
+// configLayer — один источник настроек
+type configLayer struct {
+	source string            // описание источника для --show-config
+	dir    string            // каталог файла настроек; относительно него разрешается font-path
+	values map[string]string // значения по ключам
+}
+
+// configEntry — действующее значение настройки и его источник
+type configEntry struct {
+	value  string
+	source string
+}
+
+// userConfigPath возвращает путь к файлу настроек пользователя:
+// $XDG_CONFIG_HOME/ascii-art/config или ~/.config/ascii-art/config
+func userConfigPath() string {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "ascii-art", "config")
+}
+
+// projectConfigPath ищет .asciiartrc в текущем каталоге и его родителях
+func projectConfigPath() string {
+	dir, err := os.Getwd()
+	if err != nil {
+		return ""
+	}
+	for {
+		path := filepath.Join(dir, projectConfigName)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// readConfig читает файл настроек из строк "ключ = значение";
+// пустые строки и строки, начинающиеся с #, пропускаются. Отсутствующий файл — не ошибка.
+func readConfig(path string) (map[string]string, error) {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errorf("failed to open %s: %v", path, err)
+	}
+	defer file.Close()
+
+	values := make(map[string]string)
+	scanner := bufio.NewScanner(file)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok {
+			return nil, errorf("%s:%d: expected key = value", path, lineNum)
+		}
+		if !isConfigKey(key) {
+			return nil, errorf("%s:%d: unknown setting %q", path, lineNum, key)
+		}
+		values[key] = strings.Trim(strings.TrimSpace(value), `"`)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errorf("failed to read %s: %v", path, err)
+	}
+	return values, nil
+}
+
+// isConfigKey проверяет, известен ли ключ настроек
+func isConfigKey(key string) bool {
+	for _, k := range configKeys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// envName возвращает имя переменной окружения для ключа: font-path → ASCII_ART_FONT_PATH
+func envName(key string) string {
+	return configEnvPrefix + strings.ToUpper(strings.ReplaceAll(key, "-", "_"))
+}
+
+// configLayers собирает источники настроек от младшего к старшему:
+// файл пользователя, файл проекта, переменные окружения
+func configLayers() ([]configLayer, error) {
+	var layers []configLayer
+	for _, file := range []struct{ kind, path string }{
+		{"user config", userConfigPath()},
+		{"project config", projectConfigPath()},
+	} {
+		if file.path == "" {
+			continue
+		}
+		values, err := readConfig(file.path)
+		if err != nil {
+			return nil, err
+		}
+		if values != nil {
+			layers = append(layers, configLayer{
+				source: file.kind + " " + file.path,
+				dir:    filepath.Dir(file.path),
+				values: values,
+			})
+		}
+	}
+	env := configLayer{values: make(map[string]string)}
+	for _, key := range configKeys {
+		if value, ok := os.LookupEnv(envName(key)); ok {
+			env.values[key] = value
+		}
+	}
+	return append(layers, env), nil
+}
+
+// applyConfig применяет источники настроек по порядку через обработчики флагов,
+// так что значения проверяются так же, как в командной строке. Ключи без
+// одноимённого флага этой программой не используются и пропускаются.
+// В effective записываются действующие значения и их источники.
+func applyConfig(specs []flagSpec, layers []configLayer, effective map[string]configEntry) error {
+	for _, layer := range layers {
+		for _, key := range configKeys {
+			value, ok := layer.values[key]
+			spec := findFlag(specs, key, true)
+			if !ok || spec == nil {
+				continue
+			}
+			if key == "font-path" && layer.dir != "" {
+				value = resolvePathList(value, layer.dir)
+			}
+			source := layer.source
+			if source == "" {
+				source = "env " + envName(key)
+			}
+			if err := spec.set(value); err != nil {
+				return errorf("%s: invalid value %q for %s: %v", source, value, key, err)
+			}
+			effective[key] = configEntry{value: value, source: source}
+		}
+	}
+	return nil
+}
+
+// trackFlags оборачивает обработчики флагов, которые совпадают с ключами
+// настроек, чтобы --show-config показывал значения из командной строки
+func trackFlags(specs []flagSpec, effective map[string]configEntry) {
+	for i := range specs {
+		name, set := specs[i].name, specs[i].set
+		if !isConfigKey(name) {
+			continue
+		}
+		specs[i].set = func(value string) error {
+			effective[name] = configEntry{value: value, source: "flag --" + name}
+			return set(value)
+		}
+	}
+}
+
+// resolvePathList делает относительные каталоги списка абсолютными относительно dir
+func resolvePathList(list, dir string) string {
+	dirs := filepath.SplitList(list)
+	for i, d := range dirs {
+		if d != "" && !filepath.IsAbs(d) {
+			dirs[i] = filepath.Join(dir, d)
+		}
+	}
+	return strings.Join(dirs, string(os.PathListSeparator))
+}
+
+// resolveFont ищет файл шрифта в каталогах font-path по порядку;
+// если его там нет, возвращает name как есть (шрифт рядом с программой)
+func resolveFont(name, fontPath string) string {
+	for _, dir := range filepath.SplitList(fontPath) {
+		if dir == "" {
+			continue
+		}
+		path := filepath.Join(dir, name)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path
+		}
+	}
+	return name
+}
+
+// showConfig печатает действующие настройки программы с их источниками
+func showConfig(w io.Writer, effective map[string]configEntry) {
+	for _, key := range configKeys {
+		if entry, ok := effective[key]; ok {
+			fmt.Fprintf(w, "%-12s = %-16s # %s\n", key, entry.value, entry.source)
+		}
+	}
+}
+
+// isConfigShow сообщает, что позиционные аргументы — команда config show,
+// то же, что --show-config. Аргументы после "--" командой не считаются.
+func isConfigShow(raw, args []string) bool {
+	return len(args) == 2 && args[0] == "config" && args[1] == "show" && !slices.Contains(raw, "--")
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestApplyLineEnds(t *testing.T) {
+	tests := []struct {
+		name, text string
+		opts       lineEndOptions
+		want       string
+	}{
+		{"unchanged", "ab  \ncd\n", lineEndOptions{}, "ab  \ncd\n"},
+		{"show ends", "ab  \ncd\n", lineEndOptions{showEnds: true}, "ab  $\ncd$\n"},
+		{"trim", "ab  \ncd\t\n", lineEndOptions{trimTrailing: true}, "ab\ncd\n"},
+		{"trim and show", "ab  \n  \n", lineEndOptions{showEnds: true, trimTrailing: true}, "ab$\n$\n"},
+		{"empty line", "\n", lineEndOptions{showEnds: true}, "$\n"},
+		{"no final newline", "ab ", lineEndOptions{showEnds: true}, "ab "},
+		{"leading spaces kept", "  ab  \n", lineEndOptions{trimTrailing: true}, "  ab\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := applyLineEnds(tt.text, tt.opts); got != tt.want {
+				t.Errorf("applyLineEnds(%q, %+v) = %q, want %q", tt.text, tt.opts, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderTextWidth(t *testing.T) {
+	tests := []struct {
+		name, text string
+		width      int
+	}{
+		{"one word", "hello", 0},
+		{"trailing spaces", "hi  ", 0},
+		{"two lines", "ab\ncd", 0},
+		{"wrapped", "hello world again", 40},
+		{"long word", "abcdefghijklmnop", 30},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ascii := NewASCIIArt()
+			if err := ascii.LoadFont("standard.txt"); err != nil {
+				t.Fatal(err)
+			}
+			ascii.width = tt.width
+			art := strings.TrimSuffix(ascii.RenderText(tt.text), "\n")
+			lines := strings.Split(art, "\n")
+			if len(lines)%8 != 0 {
+				t.Fatalf("got %d rows, want a multiple of 8", len(lines))
+			}
+			// Все строки одной строки баннера одинаковой ширины, вместе с
+			// хвостовыми пробелами, и не шире ширины переноса
+			for i := 0; i < len(lines); i += 8 {
+				want := displayWidth(lines[i])
+				for j, line := range lines[i : i+8] {
+					if got := displayWidth(line); got != want {
+						t.Errorf("row %d is %d columns wide, want %d", i+j, got, want)
+					}
+				}
+				if tt.width > 0 && want > tt.width {
+					t.Errorf("row %d is %d columns wide, more than --width %d", i, want, tt.width)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+// Коды завершения, по которым скрипты различают класс ошибки
+const (
+	exitUsage       = 2 // неверные аргументы или флаги
+	exitFontMissing = 3 // шрифт не найден
+	exitFontInvalid = 4 // файл шрифта повреждён
+	exitUnsupported = 5 // в тексте есть символы, которых нет в шрифте
+	exitIO          = 6 // ошибка чтения или записи
+)
+
+// exitStatuses описывает коды завершения для man-страницы
+var exitStatuses = []struct {
+	code    int
+	meaning string
+}{
+	{0, "Success."},
+	{exitUsage, "Invalid arguments or flags."},
+	{exitFontMissing, "The font was not found."},
+	{exitFontInvalid, "The font file is malformed."},
+	{exitUnsupported, "The text has characters missing from the font (with --unsupported=error)."},
+	{exitIO, "A file or stream could not be read or written."},
+}
+
+// errInvalidFont оборачивает ошибки разбора файла шрифта
+var errInvalidFont = message("invalid font file")
+
+// errUnknownFont оборачивает ошибки выбора несуществующего баннера
+var errUnknownFont = message("unknown font")
+
+// fail выводит сообщение об ошибке в stderr и завершает программу с кодом code
+func fail(code int, format string, args ...any) {
+	fmt.Fprintln(os.Stderr, tr("Error:"), fmt.Sprintf(tr(format), args...))
+	os.Exit(code)
+}
+
+// exitCodeFor определяет код завершения по ошибке выбора или загрузки шрифта
+// либо по ошибке о неподдерживаемых символах
+func exitCodeFor(err error) int {
+	switch {
+	case errors.Is(err, errUnsupported):
+		return exitUnsupported
+	case errors.Is(err, errUnknownFont), errors.Is(err, os.ErrNotExist):
+		return exitFontMissing
+	case errors.Is(err, errInvalidFont):
+		return exitFontInvalid
+	}
+	return exitIO
+}
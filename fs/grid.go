@@ -0,0 +1,186 @@
+package main
+
+import (
+	"strings"
+)
+
+// Cell — один баннер в сетке: готовый ASCII-арт (например, результат RenderText)
+// и его выравнивание внутри колонки
+type Cell struct {
+	Art   string
+	Align string // left, center или right
+}
+
+// Grid описывает раскладку нескольких баннеров по сетке, например
+// "CPU 42%" и "MEM 73%" рядом друг с другом
+type Grid struct {
+	Columns int    // число колонок; баннеры заполняют сетку построчно
+	Gutter  int    // пробелы между колонками
+	RowGap  int    // пустые строки между рядами
+	VAlign  string // вертикальное выравнивание баннеров в ряду: top, middle или bottom
+}
+
+// artRows разбивает ASCII-арт на строки, отбрасывая завершающий перенос
+func artRows(art string) []string {
+	if art == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(art, "\n"), "\n")
+}
+
+// blockWidth возвращает ширину самой широкой строки блока
+func blockWidth(rows []string) int {
+	width := 0
+	for _, row := range rows {
+		width = max(width, displayWidth(row))
+	}
+	return width
+}
+
+// concatRows приписывает блок right справа к блоку left построчно, через gap.
+// Блоки разной высоты дополняются пустыми строками, короткие строки left —
+// пробелами до width, ширины left, которую ведёт вызывающий: так строки left
+// не измеряются заново при каждой новой ячейке.
+func concatRows(left []string, width int, right []string, gap string) []string {
+	height := max(len(left), len(right))
+	rows := make([]string, height)
+	for i := range rows {
+		if i < len(left) {
+			rows[i] = left[i]
+		}
+		if extra := width - displayWidth(rows[i]); extra > 0 {
+			rows[i] += strings.Repeat(" ", extra)
+		}
+		if i < len(right) {
+			rows[i] += gap + right[i]
+		} else if gap != "" {
+			rows[i] += gap
+		}
+	}
+	return rows
+}
+
+// Compose раскладывает баннеры по сетке. Ширина колонки равна ширине самого
+// широкого баннера в ней, высота ряда — высоте самого высокого баннера в ряду,
+// так что баннеры разных шрифтов и высот выстраиваются ровно.
+func (g Grid) Compose(cells []Cell) string {
+	if len(cells) == 0 {
+		return ""
+	}
+	columns := max(g.Columns, 1)
+
+	blocks := make([][]string, len(cells))
+	colWidths := make([]int, min(columns, len(cells)))
+	for i, cell := range cells {
+		blocks[i] = artRows(cell.Art)
+		colWidths[i%columns] = max(colWidths[i%columns], blockWidth(blocks[i]))
+	}
+
+	gutter := strings.Repeat(" ", g.Gutter)
+	var result strings.Builder
+	for start := 0; start < len(cells); start += columns {
+		end := min(start+columns, len(cells))
+		height := 0
+		for _, block := range blocks[start:end] {
+			height = max(height, len(block))
+		}
+		if start > 0 {
+			result.WriteString(strings.Repeat("\n", g.RowGap))
+		}
+
+		var row []string
+		rowWidth := 0 // ширина уже собранной части ряда
+		for i := start; i < end; i++ {
+			cell := placeCell(blocks[i], cells[i].Align, g.VAlign, colWidths[i-start], height)
+			if i == start {
+				row = cell
+			} else {
+				row = concatRows(row, rowWidth, cell, gutter)
+				rowWidth += g.Gutter
+			}
+			rowWidth += colWidths[i-start]
+		}
+		for _, line := range row {
+			result.WriteString(line)
+			result.WriteString("\n")
+		}
+	}
+	return result.String()
+}
+
+// placeCell выравнивает блок внутри ячейки размером width x height
+func placeCell(block []string, align, valign string, width, height int) []string {
+	top := 0
+	switch valign {
+	case "middle":
+		top = (height - len(block)) / 2
+	case "bottom":
+		top = height - len(block)
+	}
+	blockW := blockWidth(block)
+	cell := make([]string, height)
+	for i := range cell {
+		line := ""
+		if j := i - top; j >= 0 && j < len(block) {
+			line = block[j] + strings.Repeat(" ", blockW-displayWidth(block[j]))
+		}
+		cell[i] = alignInColumn(line, align, width)
+		if line == "" {
+			cell[i] = strings.Repeat(" ", width)
+		}
+	}
+	return cell
+}
+
+// parseCell разбирает аргумент ячейки вида ТЕКСТ[@ШРИФТ[@ВЫРАВНИВАНИЕ]].
+// Суффиксы отделяются, только если это известный шрифт или выравнивание,
+// поэтому "user@host" остаётся текстом. Без шрифта берётся defaultBanner.
+func parseCell(arg, defaultBanner, fontPath string) (text, banner, align string) {
+	text, banner, align = arg, defaultBanner, "left"
+	if i := strings.LastIndex(text, "@"); i >= 0 && isValidColumnAlign(text[i+1:]) {
+		if j := strings.LastIndex(text[:i], "@"); j >= 0 {
+			if _, err := fontFileFor(text[j+1:i], fontPath); err == nil {
+				return text[:j], text[j+1 : i], text[i+1:]
+			}
+		}
+		text, align = text[:i], text[i+1:]
+	}
+	if i := strings.LastIndex(text, "@"); i >= 0 {
+		if _, err := fontFileFor(text[i+1:], fontPath); err == nil {
+			text, banner = text[:i], text[i+1:]
+		}
+	}
+	return text, banner, align
+}
+
+// renderGrid рендерит каждый аргумент своим шрифтом и раскладывает результаты по сетке
+func renderGrid(args []string, opts cliOptions) (string, error) {
+	// Шрифт ячеек без @ШРИФТ — из --banner, а без него — из настроек
+	cellBanner := opts.banner
+	if cellBanner == "" {
+		cellBanner = opts.defaultBanner
+	}
+	fonts := make(map[string]*ASCIIArt) // каждый шрифт загружаем один раз
+	cells := make([]Cell, 0, len(args))
+	for _, arg := range args {
+		text, banner, align := parseCell(arg, cellBanner, opts.fontPath)
+		ascii, ok := fonts[banner]
+		if !ok {
+			fontFile, err := fontFileFor(banner, opts.fontPath)
+			if err != nil {
+				return "", err
+			}
+			ascii = NewASCIIArt()
+			if err := ascii.LoadFont(fontFile); err != nil {
+				return "", errorf("error loading font file '%s': %w", fontFile, err)
+			}
+			fonts[banner] = ascii
+		}
+		text, err := ascii.applyCharPolicy(text, opts.chars)
+		if err != nil {
+			return "", errorf("%s: %w", arg, err)
+		}
+		cells = append(cells, Cell{Art: ascii.RenderText(text), Align: align})
+	}
+	return opts.grid.Compose(cells), nil
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"io"
+	"os"
+	"strings"
+)
+
+// stdinName — имя, означающее стандартный ввод вместо строки или файла
+const stdinName = "-"
+
+// readInput читает текст из файла или, если path равен "-", из стандартного ввода.
+// Настоящие переводы строк становятся разрывами строк баннера; последний перевод
+// строки (как в выводе `hostname`) отбрасывается, а \r\n приводится к \n.
+func readInput(path string) (string, error) {
+	var data []byte
+	var err error
+	if path == stdinName {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		if path == stdinName {
+			return "", errorf("failed to read stdin: %v", err)
+		}
+		return "", errorf("failed to read %s: %v", path, err)
+	}
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+	return strings.TrimSuffix(text, "\n"), nil
+}
+
+// stdinIsPiped сообщает, перенаправлен ли стандартный ввод (канал или файл, а не терминал)
+func stdinIsPiped() bool {
+	info, err := os.Stdin.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice == 0
+}
+
+// inputText выбирает источник текста: файл из --input, стандартный ввод
+// (аргумент "-" или отсутствие строки при перенаправленном вводе) или первый
+// аргумент. Возвращает текст, оставшиеся аргументы и false, если текста нет.
+func inputText(inputFile string, args []string) (string, []string, bool, error) {
+	switch {
+	case inputFile != "":
+		text, err := readInput(inputFile)
+		return text, args, true, err
+	case len(args) > 0 && args[0] == stdinName:
+		text, err := readInput(stdinName)
+		return text, args[1:], true, err
+	case len(args) == 0 && stdinIsPiped():
+		text, err := readInput(stdinName)
+		return text, args, true, err
+	case len(args) == 0:
+		return "", args, false, nil
+	}
+	return expandNewlines(args[0]), args[1:], true, nil
+}
+
+// expandNewlines превращает последовательности \n из аргумента командной строки
+// в переводы строк. Текст из stdin и файлов приходит с настоящими переводами
+// строк и передаётся как есть, чтобы обратная косая черта в нём сохранилась.
+func expandNewlines(arg string) string {
+	return strings.ReplaceAll(arg, `\n`, "\n")
+}
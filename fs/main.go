@@ -3,6 +3,7 @@ package main
 import (
 	"bufio"   // Для построчного чтения файла
 	"fmt"     // Для форматированного ввода-вывода
+	"io"      // Для вывода справки в stdout или stderr
 	"os"      // Для работы с файлами и аргументами командной строки
 	"strings" // Для работы со строками
 )
@@ -15,6 +16,7 @@ type ASCIIChar struct {
 // ASCIIArt - основная структура, которая хранит весь шрифт
 type ASCIIArt struct {
 	chars map[rune]ASCIIChar // Карта, связывающая каждый символ (руну) с его ASCII-представлением
+	width int                // Ширина переноса строк в колонках, 0 — без переноса
 }
 
 // NewASCIIArt - создает новый экземпляр структуры
@@ -30,7 +32,7 @@ func (a *ASCIIArt) LoadFont(filename string) error {
 	// Открываем файл шрифта
 	file, err := os.Open(filename)
 	if err != nil {
-		return fmt.Errorf("failed to open %s: %v", filename, err)
+		return errorf("failed to open %s: %w", filename, err)
 	}
 	defer file.Close() // Гарантируем закрытие файла после завершения функции
 	// Список всех поддерживаемых символов в порядке ASCII
@@ -55,6 +57,9 @@ func (a *ASCIIArt) LoadFont(filename string) error {
 		line := scanner.Text()
 		if line == "" { // Пустая строка означает конец текущего символа
 			if lineIndex > 0 && charIndex < len(supportedChars) {
+				if lineIndex < 8 {
+					return errorf("%w %s: character %q has %d lines instead of 8", errInvalidFont, filename, supportedChars[charIndex], lineIndex)
+				}
 				a.chars[supportedChars[charIndex]] = ASCIIChar{lines: currentLines}
 				charIndex++                // Переходим к следующему символу
 				lineIndex = 0              // Сбрасываем счётчик строк
@@ -64,12 +69,25 @@ func (a *ASCIIArt) LoadFont(filename string) error {
 			if lineIndex < 8 { // Сохраняем строку, если не превышен лимит в 8 строк
 				currentLines[lineIndex] = line
 				lineIndex++
+			} else if charIndex < len(supportedChars) {
+				return errorf("%w %s: character %q has more than 8 lines", errInvalidFont, filename, supportedChars[charIndex])
 			}
 		}
 	}
 	// Обрабатываем последний символ в файле
 	if lineIndex > 0 && charIndex < len(supportedChars) {
+		if lineIndex < 8 {
+			return errorf("%w %s: character %q has %d lines instead of 8", errInvalidFont, filename, supportedChars[charIndex], lineIndex)
+		}
 		a.chars[supportedChars[charIndex]] = ASCIIChar{lines: currentLines}
+		charIndex++
+	}
+	if err := scanner.Err(); err != nil {
+		return errorf("failed to read %s: %w", filename, err)
+	}
+	// Шрифт должен содержать все символы от пробела до тильды
+	if charIndex < len(supportedChars) {
+		return errorf("%w %s: %d of %d characters", errInvalidFont, filename, charIndex, len(supportedChars))
 	}
 	return nil
 }
@@ -82,37 +100,40 @@ func (a *ASCIIArt) RenderText(input string) string {
 	if input == "" {
 		return "" // Пустой ввод
 	}
-	// if input "\n" then return "$"
-	if input == "\\n" {
-		return "$" // Только перенос строки
+	// Только перенос строки; отметить его символом $ можно флагом --show-ends
+	if input == "\n" {
+		return "\n"
 	}
 
-	// Разделяем ввод на строки по символам \n
-	lines := strings.Split(strings.ReplaceAll(input, "\\n", "\n"), "\n")
+	// Разделяем ввод на строки; \n из аргумента уже заменён переводом строки
+	lines := strings.Split(input, "\n")
 	// Обрабатываем каждую строку ввода
 	for i, line := range lines {
 		if line == "" { // Обработка пустых строк
 			result.WriteString("\n")
 			continue
 		}
-		// Преобразуем текущую строку в ASCII-арт
-		artLines := [8]string{} // Хранит 8 строк текущего блока
-		for _, char := range line {
-			if art, exists := a.chars[char]; exists {
-				// Строим каждую строку ASCII-арта
-				for j := 0; j < 8; j++ {
-					artLines[j] += art.lines[j]
+		// Переносим строку по словам, если её ASCII-арт не помещается в ширину
+		for _, part := range a.wrapLine(line, a.width) {
+			// Преобразуем текущую часть строки в ASCII-арт
+			var artLines [8]strings.Builder // Хранит 8 строк текущего блока
+			for _, char := range line[part.start:part.end] {
+				if art, exists := a.chars[char]; exists {
+					// Строим каждую строку ASCII-арта
+					for j := 0; j < 8; j++ {
+						artLines[j].WriteString(art.lines[j])
+					}
 				}
 			}
-		}
-		// Добавляем $ в конец каждой строки и соединяем переносами строк
-		for j := 0; j < 8; j++ {
-			result.WriteString(artLines[j])
-			result.WriteString("\n")
+			// Соединяем строки переносами; $ в конце строк добавляет applyLineEnds
+			for j := 0; j < 8; j++ {
+				result.WriteString(artLines[j].String())
+				result.WriteString("\n")
+			}
 		}
 		// Обрабатываем пробелы между блоками текста
 		// Добавляем дополнительный пробел только если не в конце или если ввод заканчивается на \n
-		if i < len(lines)-1 || (len(input) >= 2 && input[len(input)-2:] == "\\n") {
+		if i < len(lines)-1 || strings.HasSuffix(input, "\n") {
 			result.WriteString("")
 		}
 	}
@@ -120,36 +141,288 @@ func (a *ASCIIArt) RenderText(input string) string {
 }
 
 func main() {
-	if len(os.Args) < 2 { // Проверяем правильное количество аргументов
+	// Язык сообщений выбирается до разбора флагов (см. messages.go)
+	lang = detectLang(os.Args[1:])
+	// Флаги можно указывать в любом порядке, до и после строки.
+	// Ошибки выводятся в stderr, код завершения зависит от класса ошибки (см. exit.go)
+	opts, args, err := parseOptions(os.Args[1:])
+	if err != nil {
+		fail(exitUsage, "%v", err)
+	}
+	if opts.help {
+		printUsage(os.Stdout)
+		return
+	}
+	if opts.configErr != nil {
+		fail(exitUsage, "%v", opts.configErr)
+	}
+	// config show — то же, что --show-config
+	if opts.action == "" && isConfigShow(os.Args[1:], args) {
+		opts.action, args = "show-config", nil
+	}
+	// Флаги действий выполняются вместо рендеринга и не принимают текста
+	if opts.action != "" {
+		if len(args) > 0 {
+			fail(exitUsage, "unexpected argument %q", args[0])
+		}
+		switch opts.action {
+		case "show-config":
+			showConfig(os.Stdout, opts.settings)
+		case "completion":
+			writeCompletion(os.Stdout, opts.shell, programName(), (&cliOptions{}).flagSpecs())
+		case "man":
+			writeManPage(os.Stdout, programName(), (&cliOptions{}).flagSpecs())
+		case "list-fonts":
+			for _, font := range discoverFonts(opts.fontPath) {
+				fmt.Println(font.name)
+			}
+		}
 		return
 	}
 
-	// Получаем имя файла шрифта из аргументов командной строки
-	input := os.Args[1] // Получаем входной текст из аргументов командной строки
-	fontFile := "standard.txt"
-
-	// Проверяем, какой тип шрифта был указан
-	if len(os.Args) > 2 {
-		switch os.Args[2] {
-		case "standard":
-			fontFile = "standard.txt"
-		case "shadow":
-			fontFile = "shadow.txt"
-		case "thinkertoy":
-			fontFile = "thinkertoy.txt"
-		default:
-			fmt.Printf("Error: Unknown font type '%s'. Supported types are: standard, shadow, thinkertoy.\n", os.Args[2])
-			return
+	// Режим сетки: каждый аргумент — отдельный баннер;
+	// без аргументов ячейки читаются построчно из --input или stdin
+	if opts.grid.Columns > 0 {
+		for i, arg := range args {
+			args[i] = expandNewlines(arg)
 		}
+		if len(args) == 0 || opts.input != "" {
+			text, _, ok, err := inputText(opts.input, nil)
+			if err != nil {
+				fail(exitIO, "%v", err)
+			}
+			if ok && text != "" {
+				args = append(strings.Split(text, "\n"), args...)
+			}
+		}
+		output, err := renderGrid(args, opts)
+		if err != nil {
+			fail(exitCodeFor(err), "%v", err)
+		}
+		fmt.Print(applyLineEnds(output, opts.ends))
+		return
+	}
+
+	// Получаем входной текст (из аргумента, файла или stdin) и тип баннера
+	input, args, ok, err := inputText(opts.input, args)
+	if err != nil {
+		fail(exitIO, "%v", err)
+	}
+	if !ok { // Проверяем правильное количество аргументов
+		printUsage(os.Stderr)
+		os.Exit(exitUsage)
+	}
+	if len(args) > 1 {
+		fail(exitUsage, "unexpected argument %q", args[1])
+	}
+	banner := opts.banner
+	if len(args) == 1 {
+		if banner != "" {
+			fail(exitUsage, "banner given twice: --banner=%s and %q", banner, args[0])
+		}
+		banner = args[0]
+	}
+	if banner == "" {
+		banner = opts.defaultBanner
+	}
+	fontFile, err := fontFileFor(banner, opts.fontPath)
+	if err != nil {
+		fail(exitFontMissing, "%v", err)
 	}
 
 	// Создаём новый обработчик ASCII-арта и загружаем шрифт
 	ascii := NewASCIIArt()
 	if err := ascii.LoadFont(fontFile); err != nil {
-		fmt.Printf("Error loading font file '%s': %v\n", fontFile, err)
-		return
+		fail(exitCodeFor(err), "loading font file '%s': %v", fontFile, err)
+	}
+	// Символы, которых нет в шрифте, обрабатываем по политике --unsupported
+	if input, err = ascii.applyCharPolicy(input, opts.chars); err != nil {
+		fail(exitUnsupported, "%v", err)
 	}
 	// Преобразуем входной текст в ASCII-арт и выводим
-	output := ascii.RenderText(input)
-	fmt.Print(output)
+	var output string
+	if opts.vertical {
+		output = ascii.RenderVertical(input, opts.columnAlign)
+	} else {
+		ascii.width = wrapWidth(opts.wrapOverride())
+		output = ascii.RenderText(input)
+	}
+	if _, err := fmt.Print(applyLineEnds(output, opts.ends)); err != nil {
+		fail(exitIO, "writing output: %v", err)
+	}
+}
+
+// fontFileFor возвращает файл шрифта для типа баннера. Файл сначала ищется
+// в каталогах fontPath; там же могут лежать шрифты с другими именами.
+func fontFileFor(banner, fontPath string) (string, error) {
+	switch banner {
+	case "standard", "shadow", "thinkertoy":
+		return resolveFont(banner+".txt", fontPath), nil
+	}
+	if banner != "" && !strings.ContainsAny(banner, `/\`) {
+		if file := resolveFont(banner+".txt", fontPath); file != banner+".txt" {
+			return file, nil
+		}
+	}
+	return "", errorf("%w type '%s', supported types are: standard, shadow, thinkertoy", errUnknownFont, banner)
+}
+
+// cliOptions хранит разобранные флаги командной строки
+type cliOptions struct {
+	ends          lineEndOptions         // отметка концов строк и удаление хвостовых пробелов
+	width         int                    // ширина переноса, 0 — по терминалу
+	noWrap        bool                   // не переносить длинные строки
+	vertical      bool                   // ставить глифы друг под другом
+	columnAlign   string                 // выравнивание глифов в вертикальном режиме
+	grid          Grid                   // раскладка по сетке; включается флагом --columns
+	banner        string                 // тип баннера, заданный флагом --banner
+	input         string                 // файл с текстом из --input, "-" — стандартный ввод
+	chars         charPolicy             // обработка символов, которых нет в шрифте
+	fontPath      string                 // каталоги для поиска шрифтов через разделитель списка путей
+	defaultBanner string                 // баннер из настроек, если его не задали аргументом или флагом
+	settings      map[string]configEntry // действующие настройки для --show-config
+	configErr     error                  // ошибка в файлах настроек или ASCII_ART_*
+	action        string                 // флаг, который выполняется вместо рендеринга, например --man
+	shell         string                 // оболочка для --completion
+	help          bool
+}
+
+// wrapOverride возвращает ширину переноса для wrapWidth: -1 отключает перенос
+func (opts cliOptions) wrapOverride() int {
+	if opts.noWrap {
+		return -1
+	}
+	return opts.width
+}
+
+// flagSpecs описывает флаги программы; обработчики сохраняют значения в opts
+func (opts *cliOptions) flagSpecs() []flagSpec {
+	aligns := []string{"left", "center", "right"}
+	valigns := []string{"top", "middle", "bottom"}
+	policies := []string{policySkip, policyError, policyPlaceholder, policyTranslit}
+	return []flagSpec{
+		{name: "input", short: "i", hasValue: true, arg: "FILE", complete: completeFile,
+			usage: "read the text from FILE ('-' is stdin); without a STRING\nthe text is read from stdin when it is piped",
+			set: func(value string) error {
+				if value == "" {
+					return errorf("missing file name")
+				}
+				opts.input = value
+				return nil
+			}},
+		{name: "banner", short: "b", hasValue: true, arg: "NAME", complete: completeFont,
+			usage: "standard, shadow, thinkertoy or a font from the font path",
+			set: func(value string) error {
+				opts.banner = value // неизвестный баннер — ошибка шрифта, а не флага
+				return nil
+			}},
+		{name: "width", short: "w", hasValue: true, arg: "N",
+			usage: "wrap long text to N columns (default: terminal width)", set: setInt(&opts.width, 1)},
+		{name: "no-wrap", usage: "never wrap long text", set: setTrue(&opts.noWrap)},
+		{name: "vertical", short: "v", usage: "stack glyphs vertically, one column per line", set: setTrue(&opts.vertical)},
+		{name: "vertical-align", hasValue: true, arg: "A", values: aligns,
+			usage: "left, center or right within the column",
+			set: func(value string) error {
+				opts.vertical = true
+				return setOneOf(&opts.columnAlign, aligns...)(value)
+			}},
+		{name: "columns", hasValue: true, arg: "N",
+			usage: "lay out every argument TEXT[@FONT[@ALIGN]] in an N-column grid", set: setInt(&opts.grid.Columns, 1)},
+		{name: "gutter", short: "g", hasValue: true, arg: "N",
+			usage: "spaces between grid columns (default 2)", set: setInt(&opts.grid.Gutter, 0)},
+		{name: "row-gap", hasValue: true, arg: "N", usage: "empty lines between grid rows", set: setInt(&opts.grid.RowGap, 0)},
+		{name: "grid-valign", hasValue: true, arg: "A", values: valigns,
+			usage: "top, middle or bottom within a grid row", set: setOneOf(&opts.grid.VAlign, valigns...)},
+		{name: "unsupported", short: "u", hasValue: true, arg: "MODE", values: policies,
+			usage: "characters missing from the font: skip (default), error,\nplaceholder or translit (é→e, ß→ss, curly quotes→straight)",
+			set:   setOneOf(&opts.chars.mode, policies...)},
+		{name: "placeholder", hasValue: true, arg: "CHAR",
+			usage: "draw CHAR (or 'box') for missing characters (default '?')",
+			set: func(value string) error {
+				opts.chars.mode = policyPlaceholder
+				opts.chars.placeholder = value
+				return validatePlaceholder(value)
+			}},
+		{name: "font-path", hasValue: true, arg: "DIRS", complete: completeDir,
+			usage: "directories searched for banner files first (':'-separated)",
+			set: func(value string) error {
+				opts.fontPath = value
+				return nil
+			}},
+		{name: "show-ends", short: "E", usage: "mark the end of every line with $", set: setTrue(&opts.ends.showEnds)},
+		{name: "trim-trailing", short: "T", usage: "strip trailing spaces", set: setTrue(&opts.ends.trimTrailing)},
+		{name: "show-config", usage: "print the effective settings and where they come from",
+			set: setAction(&opts.action, "show-config")},
+		{name: "completion", hasValue: true, arg: "SHELL", values: completionShells,
+			usage: "print a completion script for bash, zsh or fish",
+			set: func(value string) error {
+				if err := setOneOf(&opts.shell, completionShells...)(value); err != nil {
+					return err
+				}
+				return setAction(&opts.action, "completion")(value)
+			}},
+		{name: "man", usage: "print the manual page in roff format", set: setAction(&opts.action, "man")},
+		{name: "list-fonts", usage: "list the fonts found in the font path and the bundled ones",
+			set: setAction(&opts.action, "list-fonts")},
+		{name: "lang", hasValue: true, arg: "LANG", values: languages,
+			usage: "language of help and messages: en or ru (default: from LC_ALL or LANG)",
+			set:   setOneOf(&lang, languages...)},
+		{name: "help", short: "h", usage: "show this help", set: setTrue(&opts.help)},
+	}
+}
+
+// parseOptions разбирает аргументы командной строки (без имени программы)
+// и возвращает флаги и позиционные аргументы
+func parseOptions(args []string) (cliOptions, []string, error) {
+	opts := cliOptions{
+		columnAlign: "left",
+		grid:        Grid{Gutter: 2, VAlign: "top"},
+		chars:       charPolicy{mode: policySkip, placeholder: "?"},
+	}
+	specs := opts.flagSpecs()
+
+	// Настройки применяются от младших к старшим: встроенные, файл пользователя,
+	// файл проекта, переменные окружения и, наконец, флаги командной строки
+	opts.settings = map[string]configEntry{
+		"banner":      {"standard", "built-in"},
+		"width":       {"terminal", "built-in"},
+		"font-path":   {"", "built-in"},
+		"unsupported": {policySkip, "built-in"},
+		"placeholder": {"?", "built-in"},
+	}
+	layers, err := configLayers()
+	opts.banner = "standard"
+	if err == nil {
+		err = applyConfig(specs, layers, opts.settings)
+	}
+	// Ошибку в настройках сообщает main после --help, чтобы справка
+	// открывалась и при испорченном .asciiartrc
+	opts.configErr = err
+	opts.defaultBanner, opts.banner = opts.banner, ""
+
+	trackFlags(specs, opts.settings)
+	positional, err := parseFlags(args, specs)
+	if opts.chars.mode != opts.settings["unsupported"].value {
+		// --placeholder включает режим placeholder
+		opts.settings["unsupported"] = configEntry{opts.chars.mode, opts.settings["placeholder"].source}
+	}
+	return opts, positional, err
+}
+
+// usageSynopses — аргументы программы в строке Usage и в man-странице
+var usageSynopses = []string{"[OPTIONS] [STRING|-] [BANNER]"}
+
+// usageColumn — колонка, с которой в справке начинаются описания флагов
+const usageColumn = 27
+
+// printUsage выводит инструкцию по использованию в w
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, tr("Usage:"), "go run . "+usageSynopses[0])
+	fmt.Fprintln(w, "\n"+tr("Options (in any order, --flag=value or --flag value; -- ends options):"))
+	printFlags(w, (&cliOptions{}).flagSpecs(), usageColumn)
+	// Второй пример выравнивается под первым, какой бы длины ни была метка
+	label := tr("EX:")
+	fmt.Fprintln(w, "\n"+label, "go run . something standard")
+	fmt.Fprintln(w, strings.Repeat(" ", len([]rune(label))+1)+"hostname | go run . -b shadow")
+	fmt.Fprintln(w, "\n"+tr("Defaults for banner, width, font-path, unsupported and placeholder are read from\n$XDG_CONFIG_HOME/ascii-art/config, .asciiartrc and ASCII_ART_* variables."))
 }
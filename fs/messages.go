@@ -0,0 +1,137 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+)
+
+// languages — языки сообщений; английский — язык исходных строк
+var languages = []string{"en", "ru"}
+
+// lang — язык справки, предупреждений и ошибок. Определяется в начале main
+// по --lang или по LC_ALL, LC_MESSAGES и LANG, а флаг --lang потом проверяет значение.
+var lang = "en"
+
+// detectLang выбирает язык сообщений до разбора флагов, чтобы на нужном языке
+// были и ошибки самого разбора: сначала --lang, затем переменные локали в порядке POSIX
+func detectLang(args []string) string {
+	for i, arg := range args {
+		if arg == "--" {
+			break
+		}
+		value, ok := strings.CutPrefix(arg, "--lang=")
+		if !ok && arg == "--lang" && i+1 < len(args) {
+			value, ok = args[i+1], true
+		}
+		if ok && slices.Contains(languages, value) {
+			return value
+		}
+	}
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if locale := os.Getenv(name); locale != "" {
+			if strings.HasPrefix(strings.ToLower(locale), "ru") {
+				return "ru"
+			}
+			return "en"
+		}
+	}
+	return "en"
+}
+
+// tr возвращает сообщение на текущем языке; строки без перевода остаются английскими
+func tr(s string) string {
+	if lang == "ru" {
+		if translated, ok := russian[s]; ok {
+			return translated
+		}
+	}
+	return s
+}
+
+// errorf — fmt.Errorf с переводом формата
+func errorf(format string, args ...any) error {
+	return fmt.Errorf(tr(format), args...)
+}
+
+// message — ошибка-константа, которая переводится при выводе, поэтому её можно
+// объявить до выбора языка и сравнивать через errors.Is
+type message string
+
+func (m message) Error() string { return tr(string(m)) }
+
+// russian — перевод сообщений на русский, ключ — английская строка из кода
+var russian = map[string]string{
+	// config.go
+	"failed to open %s: %v":           "не удалось открыть %s: %v",
+	"%s:%d: expected key = value":     "%s:%d: ожидается ключ = значение",
+	"%s:%d: unknown setting %q":       "%s:%d: неизвестная настройка %q",
+	"failed to read %s: %v":           "не удалось прочитать %s: %v",
+	"%s: invalid value %q for %s: %v": "%s: недопустимое значение %q для %s: %v",
+
+	// exit.go
+	"invalid font file": "повреждённый файл шрифта",
+	"unknown font":      "неизвестный шрифт",
+	"Error:":            "Ошибка:",
+
+	// flags.go
+	"unknown flag %q (use -- before text that starts with '-')": "неизвестный флаг %q (перед текстом, начинающимся с '-', поставьте --)",
+	"flag %s does not take a value (got %q)":                    "флаг %s не принимает значения (получено %q)",
+	"flag %s needs a value":                                     "флагу %s нужно значение",
+	"invalid value %q for %s: %v":                               "недопустимое значение %q для %s: %v",
+	"cannot be combined with --%s":                              "нельзя использовать вместе с --%s",
+	"not a number":                                              "не число",
+	"must be at least %d":                                       "должно быть не меньше %d",
+	"must be one of %s":                                         "должно быть одним из: %s",
+
+	// grid.go
+	"error loading font file '%s': %w": "ошибка загрузки файла шрифта '%s': %w",
+
+	// input.go
+	"failed to read stdin: %v": "не удалось прочитать stdin: %v",
+
+	// main.go
+	"failed to open %s: %w":                                           "не удалось открыть %s: %w",
+	"%w %s: character %q has %d lines instead of 8":                   "%w %s: у символа %q %d строк вместо 8",
+	"%w %s: character %q has more than 8 lines":                       "%w %s: у символа %q больше 8 строк",
+	"failed to read %s: %w":                                           "не удалось прочитать %s: %w",
+	"%w %s: %d of %d characters":                                      "%w %s: %d символов из %d",
+	"unexpected argument %q":                                          "лишний аргумент %q",
+	"banner given twice: --banner=%s and %q":                          "баннер указан дважды: --banner=%s и %q",
+	"loading font file '%s': %v":                                      "при загрузке файла шрифта '%s': %v",
+	"writing output: %v":                                              "при выводе результата: %v",
+	"%w type '%s', supported types are: standard, shadow, thinkertoy": "%w: тип '%s', поддерживаются: standard, shadow, thinkertoy",
+	"read the text from FILE ('-' is stdin); without a STRING\nthe text is read from stdin when it is piped": "читать текст из FILE ('-' — stdin); без STRING текст\nчитается из stdin, если ввод перенаправлен",
+	"missing file name": "не указано имя файла",
+	"standard, shadow, thinkertoy or a font from the font path": "standard, shadow, thinkertoy или шрифт из font-path",
+	"wrap long text to N columns (default: terminal width)":     "переносить длинный текст по N колонкам (по умолчанию ширина терминала)",
+	"never wrap long text":                                           "никогда не переносить длинный текст",
+	"stack glyphs vertically, one column per line":                   "ставить символы друг под другом, по колонке на строку",
+	"left, center or right within the column":                        "left, center или right внутри колонки",
+	"lay out every argument TEXT[@FONT[@ALIGN]] in an N-column grid": "разместить аргументы TEXT[@FONT[@ALIGN]] в сетке из N колонок",
+	"spaces between grid columns (default 2)":                        "пробелов между колонками сетки (по умолчанию 2)",
+	"empty lines between grid rows":                                  "пустых строк между рядами сетки",
+	"top, middle or bottom within a grid row":                        "top, middle или bottom внутри ряда сетки",
+	"characters missing from the font: skip (default), error,\nplaceholder or translit (é→e, ß→ss, curly quotes→straight)": "символы, которых нет в шрифте: skip (по умолчанию), error,\nplaceholder или translit (é→e, ß→ss, фигурные кавычки→прямые)",
+	"draw CHAR (or 'box') for missing characters (default '?')":                                                            "рисовать CHAR (или 'box') вместо отсутствующих (по умолчанию '?')",
+	"directories searched for banner files first (':'-separated)":                                                          "каталоги, где сначала ищутся файлы баннеров (через ':')",
+	"mark the end of every line with $":                                                                                    "отметить конец каждой строки знаком $",
+	"strip trailing spaces":                                                                                                "удалить пробелы в конце строк",
+	"print the effective settings and where they come from":                                                                "вывести действующие настройки и их источники",
+	"print a completion script for bash, zsh or fish":                                                                      "вывести скрипт дополнения для bash, zsh или fish",
+	"print the manual page in roff format":                                                                                 "вывести man-страницу в формате roff",
+	"list the fonts found in the font path and the bundled ones":                                                           "вывести шрифты из font-path и встроенные",
+	"language of help and messages: en or ru (default: from LC_ALL or LANG)":                                               "язык справки и сообщений: en или ru (по умолчанию из LC_ALL или LANG)",
+	"show this help": "показать эту справку",
+	"Usage:":         "Использование:",
+	"Options (in any order, --flag=value or --flag value; -- ends options):": "Флаги (в любом порядке, --флаг=значение или --флаг значение; -- завершает флаги):",
+	"EX:": "Пример:",
+	"Defaults for banner, width, font-path, unsupported and placeholder are read from\n$XDG_CONFIG_HOME/ascii-art/config, .asciiartrc and ASCII_ART_* variables.": "Значения по умолчанию для banner, width, font-path, unsupported и placeholder\nберутся из $XDG_CONFIG_HOME/ascii-art/config, .asciiartrc и переменных ASCII_ART_*.",
+
+	// unsupported.go
+	"unsupported characters":                "неподдерживаемые символы",
+	"must be a single character or %q":      "должен быть одним символом или %q",
+	"%w: placeholder %q is not in the font": "%w: заменителя %q нет в шрифте",
+	"%q (%U) at %d:%d":                      "%q (%U) в позиции %d:%d",
+}
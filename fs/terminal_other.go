@@ -0,0 +1,8 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd || dragonfly)
+
+package main
+
+// terminalWidth не поддерживается на этой платформе; используется $COLUMNS
+func terminalWidth(fd uintptr) (int, bool) {
+	return 0, false
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestTransliterate(t *testing.T) {
+	tests := []struct {
+		r    rune
+		want string
+		ok   bool
+	}{
+		{'é', "e", true},
+		{'É', "E", true},
+		{'ß', "ss", true},
+		{'æ', "ae", true},
+		{'Œ', "Oe", true},
+		{'ж', "zh", true},
+		{'Ж', "Zh", true},
+		{'Щ', "Shch", true},
+		{'ь', "", true},
+		{'Ъ', "", true},
+		{'“', "\"", true},
+		{'’', "'", true},
+		{'—', "-", true},
+		{'…', "...", true},
+		{' ', " ", true},
+		{'©', "(c)", true},
+		{'€', "EUR", true},
+		{'中', "", false},
+		{'😀', "", false},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.r), func(t *testing.T) {
+			got, ok := transliterate(tt.r)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("transliterate(%q) = %q, %v, want %q, %v", tt.r, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestApplyCharPolicy(t *testing.T) {
+	tests := []struct {
+		name, input string
+		policy      charPolicy
+		want        string
+		wantErr     string
+	}{
+		{"supported", "Hello, world!", charPolicy{mode: policyError}, "Hello, world!", ""},
+		{"skip", "a€b", charPolicy{mode: policySkip}, "ab", ""},
+		{"placeholder", "a€b", charPolicy{mode: policyPlaceholder, placeholder: "?"}, "a?b", ""},
+		{"placeholder box", "a€", charPolicy{mode: policyPlaceholder, placeholder: boxPlaceholder}, "a�", ""},
+		{"translit", "Crème brûlée — “voilà”", charPolicy{mode: policyTranslit}, "Creme brulee - \"voila\"", ""},
+		{"translit russian", "Щука ёж", charPolicy{mode: policyTranslit}, "Shchuka ezh", ""},
+		{"error", "ab€\nc€", charPolicy{mode: policyError}, "",
+			"unsupported characters: '€' (U+20AC) at 1:3, '€' (U+20AC) at 2:2"},
+		{"literal backslash n is text", "a\\n€", charPolicy{mode: policyError}, "",
+			"unsupported characters: '€' (U+20AC) at 1:4"},
+		{"translit falls back to error", "a中", charPolicy{mode: policyTranslit}, "",
+			"unsupported characters: '中' (U+4E2D) at 1:2"},
+		{"placeholder not in font", "a", charPolicy{mode: policyPlaceholder, placeholder: "€"}, "",
+			"unsupported characters: placeholder \"€\" is not in the font"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ascii := NewASCIIArt()
+			if err := ascii.LoadFont("standard.txt"); err != nil {
+				t.Fatal(err)
+			}
+			got, err := ascii.applyCharPolicy(tt.input, tt.policy)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr || !errors.Is(err, errUnsupported) {
+					t.Fatalf("applyCharPolicy(%q) error = %v, want %q", tt.input, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("applyCharPolicy(%q) = %q, %v, want %q", tt.input, got, err, tt.want)
+			}
+		})
+	}
+}
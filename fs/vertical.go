@@ -0,0 +1,82 @@
+package main
+
+import "strings"
+
+// columnGap — расстояние между колонками вертикального текста
+const columnGap = "  "
+
+// isValidColumnAlign проверяет значение --vertical-align
+func isValidColumnAlign(align string) bool {
+	return align == "left" || align == "center" || align == "right"
+}
+
+// RenderVertical ставит глифы символов друг под другом (по одному блоку из 8 строк
+// на символ) вместо того, чтобы выводить их в ряд. Каждая строка входного текста
+// становится отдельной колонкой; колонки выводятся рядом через columnGap.
+// Глифы разной ширины выравниваются внутри колонки по align: left, center или right.
+func (a *ASCIIArt) RenderVertical(input, align string) string {
+	if input == "" {
+		return ""
+	}
+
+	var columns [][]string // строки ASCII-арта каждой колонки
+	for _, line := range strings.Split(input, "\n") {
+		// Ширина колонки — ширина самого широкого глифа в ней
+		width := 0
+		for _, char := range line {
+			if art, exists := a.chars[char]; exists {
+				width = max(width, displayWidth(art.lines[0]))
+			}
+		}
+
+		var column []string
+		for _, char := range line {
+			art, exists := a.chars[char]
+			if !exists {
+				continue // как и в RenderText, символы без глифа пропускаются
+			}
+			for j := 0; j < 8; j++ {
+				column = append(column, alignInColumn(art.lines[j], align, width))
+			}
+		}
+		columns = append(columns, column)
+	}
+
+	// Колонки разной высоты дополняем пустыми строками снизу
+	height := 0
+	for _, column := range columns {
+		height = max(height, len(column))
+	}
+	var result strings.Builder
+	for row := 0; row < height; row++ {
+		for i, column := range columns {
+			if i > 0 {
+				result.WriteString(columnGap)
+			}
+			if row < len(column) {
+				result.WriteString(column[row])
+			} else if len(column) > 0 {
+				result.WriteString(strings.Repeat(" ", displayWidth(column[0])))
+			}
+		}
+		result.WriteString("\n")
+	}
+	return result.String()
+}
+
+// alignInColumn дополняет строку глифа пробелами до ширины колонки
+func alignInColumn(line, align string, width int) string {
+	extra := width - displayWidth(line)
+	if extra <= 0 {
+		return line
+	}
+	switch align {
+	case "right":
+		return strings.Repeat(" ", extra) + line
+	case "center":
+		left := extra / 2
+		return strings.Repeat(" ", left) + line + strings.Repeat(" ", extra-left)
+	default:
+		return line + strings.Repeat(" ", extra)
+	}
+}
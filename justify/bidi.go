@@ -0,0 +1,219 @@
+package main
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Text directions accepted by --direction.
+const (
+	directionLTR  = "ltr"
+	directionRTL  = "rtl"
+	directionAuto = "auto"
+)
+
+// Bidirectional character classes, a reduced set of the Unicode bidi classes.
+const (
+	bidiL  = iota // strong left-to-right: Latin and most other letters
+	bidiR         // strong right-to-left: Hebrew, Arabic and related scripts
+	bidiEN        // digits
+	bidiN         // neutrals: spaces and punctuation
+)
+
+// rtlScripts are the scripts written right to left.
+var rtlScripts = []*unicode.RangeTable{
+	unicode.Hebrew, unicode.Arabic, unicode.Syriac, unicode.Thaana, unicode.Nko,
+}
+
+// numberSeparators join the digits on both sides of them into one number,
+// as in 1.5, 1,000, 10:30, 1/2 and 2024-10-19.
+const numberSeparators = ".,:/+-"
+
+// numberTerminators belong to the number next to them, as in 50% or -5.
+const numberTerminators = "%+-"
+
+// mirrored maps brackets to their mirror image for right-to-left runs.
+var mirrored = map[rune]rune{
+	'(': ')', ')': '(',
+	'[': ']', ']': '[',
+	'{': '}', '}': '{',
+	'<': '>', '>': '<',
+}
+
+func bidiClass(r rune) int {
+	switch {
+	case unicode.IsDigit(r):
+		return bidiEN
+	case unicode.In(r, rtlScripts...):
+		return bidiR
+	case unicode.IsLetter(r):
+		return bidiL
+	}
+	return bidiN
+}
+
+// baseLevel returns the paragraph embedding level: 1 for right-to-left
+// text, 0 for left-to-right. "auto" takes the direction of the first
+// strong character.
+func baseLevel(classes []int, direction string) int {
+	switch direction {
+	case directionRTL:
+		return 1
+	case directionAuto:
+		for _, class := range classes {
+			if class == bidiL {
+				return 0
+			}
+			if class == bidiR {
+				return 1
+			}
+		}
+	}
+	return 0
+}
+
+// visualOrder reorders a line from logical order (the order it is typed)
+// into the left-to-right order its glyphs are drawn in. It follows a
+// simplified Unicode bidi algorithm: right-to-left runs are reversed,
+// numbers (with their separators and signs) and Latin text inside them keep
+// their reading order, neutrals take the direction of their surroundings
+// and brackets are mirrored inside right-to-left runs.
+func visualOrder(line, direction string) string {
+	runes := []rune(line)
+	classes := make([]int, len(runes))
+	hasRTL := false
+	for i, r := range runes {
+		classes[i] = bidiClass(r)
+		hasRTL = hasRTL || classes[i] == bidiR
+	}
+	base := baseLevel(classes, direction)
+	if base == 0 && !hasRTL {
+		return line // plain left-to-right text needs no reordering
+	}
+	baseClass := bidiL
+	if base == 1 {
+		baseClass = bidiR
+	}
+
+	// A single separator between two digits is part of the number (rule W4
+	// of the Unicode bidi algorithm), and so are signs and percent signs
+	// next to a digit (W5); otherwise each group of digits would be kept in
+	// reading order on its own and 1.5 would come out as 5.1
+	for i := 1; i+1 < len(runes); i++ {
+		if classes[i-1] == bidiEN && classes[i+1] == bidiEN && strings.ContainsRune(numberSeparators, runes[i]) {
+			classes[i] = bidiEN
+		}
+	}
+	isTerminator := func(i int) bool {
+		return classes[i] == bidiN && strings.ContainsRune(numberTerminators, runes[i])
+	}
+	for i := 0; i < len(runes); {
+		if !isTerminator(i) {
+			i++
+			continue
+		}
+		j := i
+		for j < len(runes) && isTerminator(j) {
+			j++
+		}
+		if (i > 0 && classes[i-1] == bidiEN) || (j < len(runes) && classes[j] == bidiEN) {
+			for k := i; k < j; k++ {
+				classes[k] = bidiEN
+			}
+		}
+		i = j
+	}
+
+	// Digits following Latin text (or starting a left-to-right line) are Latin
+	strong := baseClass
+	for i, class := range classes {
+		switch class {
+		case bidiL, bidiR:
+			strong = class
+		case bidiEN:
+			if strong == bidiL {
+				classes[i] = bidiL
+			}
+		}
+	}
+
+	// Neutrals between two runs of the same direction take that direction,
+	// otherwise the paragraph direction; remaining digits count as R here
+	strongOf := func(class int) int {
+		if class == bidiEN {
+			return bidiR
+		}
+		return class
+	}
+	for i := 0; i < len(classes); {
+		if classes[i] != bidiN {
+			i++
+			continue
+		}
+		j := i
+		for j < len(classes) && classes[j] == bidiN {
+			j++
+		}
+		before, after := baseClass, baseClass
+		if i > 0 {
+			before = strongOf(classes[i-1])
+		}
+		if j < len(classes) {
+			after = strongOf(classes[j])
+		}
+		resolved := baseClass
+		if before == after {
+			resolved = before
+		}
+		for k := i; k < j; k++ {
+			classes[k] = resolved
+		}
+		i = j
+	}
+
+	// Assign embedding levels: odd levels are drawn right to left
+	levels := make([]int, len(runes))
+	maxLevel := base
+	for i, class := range classes {
+		switch {
+		case base == 0 && class == bidiR:
+			levels[i] = 1
+		case base == 0 && class == bidiEN:
+			levels[i] = 2
+		case base == 1 && (class == bidiL || class == bidiEN):
+			levels[i] = 2
+		default:
+			levels[i] = base
+		}
+		maxLevel = max(maxLevel, levels[i])
+	}
+
+	// Reverse every run at or above each level, from the highest level down
+	// to the lowest odd level
+	for level := maxLevel; level >= 1; level-- {
+		for i := 0; i < len(runes); {
+			if levels[i] < level {
+				i++
+				continue
+			}
+			j := i
+			for j < len(runes) && levels[j] >= level {
+				j++
+			}
+			for a, b := i, j-1; a < b; a, b = a+1, b-1 {
+				runes[a], runes[b] = runes[b], runes[a]
+				levels[a], levels[b] = levels[b], levels[a]
+			}
+			i = j
+		}
+	}
+
+	for i, r := range runes {
+		if levels[i]%2 == 1 {
+			if m, ok := mirrored[r]; ok {
+				runes[i] = m
+			}
+		}
+	}
+	return string(runes)
+}
@@ -0,0 +1,35 @@
+package main
+
+import "testing"
+
+func TestVisualOrder(t *testing.T) {
+	tests := []struct {
+		name, line, direction, want string
+	}{
+		{"latin only", "abc (def)", directionLTR, "abc (def)"},
+		{"hebrew in latin", "abc אבג def", directionLTR, "abc גבא def"},
+		{"hebrew", "אבג דה", directionRTL, "הד גבא"},
+		{"auto picks hebrew", "אב cd", directionAuto, "cd בא"},
+		{"latin in hebrew", "אב abc 12", directionRTL, "abc 12 בא"},
+		{"decimal point", "אב 1.5 גד", directionRTL, "דג 1.5 בא"},
+		{"thousands separator", "אב 1,000", directionRTL, "1,000 בא"},
+		{"time", "אב 10:30", directionRTL, "10:30 בא"},
+		{"date", "אב 2024-10-19", directionRTL, "2024-10-19 בא"},
+		{"fraction", "אב 1/2", directionRTL, "1/2 בא"},
+		{"percent", "אב 50% גד", directionRTL, "דג 50% בא"},
+		{"sign", "אב -5 גד", directionRTL, "דג -5 בא"},
+		{"period after number", "אב 1. גד", directionRTL, "דג .1 בא"},
+		{"double separator", "אב 1..5", directionRTL, "5..1 בא"},
+		{"brackets", "אב (גד)", directionRTL, "(דג) בא"},
+		{"square brackets", "אב [גד] הו", directionRTL, "וה [דג] בא"},
+		{"brackets around number", "אב (12)", directionRTL, "(12) בא"},
+		{"brackets in latin", "abc (אב)", directionLTR, "abc (בא)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := visualOrder(tt.line, tt.direction); got != tt.want {
+				t.Errorf("visualOrder(%q, %q) = %q, want %q", tt.line, tt.direction, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,329 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// errNoTerminal is returned when the browser cannot take over the terminal.
+var errNoTerminal = message("browse needs an interactive terminal")
+
+// browserText is shown until other text is typed or given with --input.
+const browserText = "Hello"
+
+// browserKeys is the key help in the status bar.
+const browserKeys = "up/down font  left/right color  a align  t text  enter done  esc quit"
+
+// browser is the state of the full-screen font browser. It keeps a session,
+// so banners are loaded once and rendered like in the interactive mode.
+type browser struct {
+	*session
+	fonts    []fontInfo
+	selected int      // index into fonts
+	top      int      // first font shown in the list
+	colors   []string // "" (no color) and the color names
+	color    int      // index into colors
+	aligns   []string
+	align    int    // index into aligns
+	editing  bool   // the status bar is editing the text
+	input    []rune // text being edited
+}
+
+// runBrowser shows every discoverable banner on a full-screen view of the
+// terminal, with the text rendered in the selected one. Keys pick the
+// banner, color and alignment; the returned command line renders the final
+// choice, and is empty when the browser was left with Esc or Ctrl-C.
+func runBrowser(opts options, raw []string) (string, error) {
+	text := browserText
+	if opts.input != "" {
+		var err error
+		if text, err = readInput(opts.input); err != nil {
+			return "", err
+		}
+	}
+	if !isTerminal() {
+		return "", errNoTerminal
+	}
+	s, err := newSession(opts, io.Discard)
+	if err != nil {
+		return "", err
+	}
+	s.text = text
+	b := &browser{
+		session: s,
+		fonts:   discoverFonts(opts.fontPath),
+		colors:  append([]string{""}, colorNames()...),
+		aligns:  []string{"left", "center", "right", "justify"},
+	}
+	if len(b.fonts) == 0 {
+		return "", errorf("no banners found")
+	}
+	for i, font := range b.fonts {
+		if font.name == s.opts.banner {
+			b.selected = i
+		}
+	}
+	b.color = max(0, indexOf(b.colors, s.opts.color))
+	b.align = max(0, indexOf(b.aligns, s.opts.layout().align))
+
+	restore, err := makeRaw(os.Stdin.Fd())
+	if err != nil {
+		return "", errorf("%w: %v", errNoTerminal, err)
+	}
+	defer restore()
+	// Draw on the alternate screen, so the shell comes back as it was
+	os.Stdout.WriteString("\033[?1049h\033[?25l")
+	defer os.Stdout.WriteString("\033[?25h\033[?1049l")
+
+	keys := make(chan string)
+	go readKeys(os.Stdin, keys)
+	resize := make(chan os.Signal, 1)
+	notifyResize(resize)
+
+	for {
+		b.draw(os.Stdout)
+		select {
+		case <-resize:
+		case key, ok := <-keys:
+			if !ok {
+				return "", nil
+			}
+			done, accepted := b.handle(key)
+			if done && accepted {
+				return b.commandLine(raw), nil
+			}
+			if done {
+				return "", nil
+			}
+		}
+	}
+}
+
+// readKeys sends every chunk read from the terminal to keys. In raw mode a
+// chunk is one key press, an escape sequence or pasted text.
+func readKeys(r io.Reader, keys chan<- string) {
+	buf := make([]byte, 64)
+	for {
+		n, err := r.Read(buf)
+		if err != nil {
+			close(keys)
+			return
+		}
+		keys <- string(buf[:n])
+	}
+}
+
+// keyName names the special keys the browser reacts to.
+func keyName(key string) string {
+	switch key {
+	case "\033[A", "\033OA":
+		return "up"
+	case "\033[B", "\033OB":
+		return "down"
+	case "\033[C", "\033OC":
+		return "right"
+	case "\033[D", "\033OD":
+		return "left"
+	case "\r", "\n":
+		return "enter"
+	case "\033":
+		return "esc"
+	case "\x03":
+		return "ctrl-c"
+	case "\x7f", "\b":
+		return "backspace"
+	}
+	return key
+}
+
+// handle applies one key press. It reports whether the browser is done and,
+// if so, whether the choice was accepted.
+func (b *browser) handle(key string) (done, accepted bool) {
+	name := keyName(key)
+	if name == "ctrl-c" {
+		return true, false
+	}
+	if b.editing {
+		switch name {
+		case "enter":
+			if len(b.input) > 0 {
+				b.text = expandNewlines(string(b.input))
+			}
+			b.editing = false
+		case "esc":
+			b.editing = false
+		default:
+			// Typed or pasted text; several backspaces may come at once
+			for _, r := range key {
+				if keyName(string(r)) == "backspace" && len(b.input) > 0 {
+					b.input = b.input[:len(b.input)-1]
+				} else if unicode.IsPrint(r) {
+					b.input = append(b.input, r)
+				}
+			}
+		}
+		return false, false
+	}
+
+	switch name {
+	case "up", "k":
+		b.selected = (b.selected + len(b.fonts) - 1) % len(b.fonts)
+	case "down", "j":
+		b.selected = (b.selected + 1) % len(b.fonts)
+	case "right", "c":
+		b.color = (b.color + 1) % len(b.colors)
+	case "left", "C":
+		b.color = (b.color + len(b.colors) - 1) % len(b.colors)
+	case "a":
+		b.align = (b.align + 1) % len(b.aligns)
+	case "A":
+		b.align = (b.align + len(b.aligns) - 1) % len(b.aligns)
+	case "t":
+		b.editing = true
+		b.input = []rune(strings.ReplaceAll(b.text, "\n", `\n`))
+	case "enter", "q":
+		return true, true
+	case "esc":
+		return true, false
+	}
+	return false, false
+}
+
+// draw paints the whole screen: the banner list on the left, the preview on
+// the right and the status bar at the bottom.
+func (b *browser) draw(w io.Writer) {
+	cols, rows, ok := terminalSize(os.Stdout.Fd())
+	if !ok {
+		cols, rows = outputWidth(0), 24
+	}
+	height := max(1, rows-1)
+
+	b.opts.banner = b.fonts[b.selected].name
+	b.opts.color = b.colors[b.color]
+	b.opts.align = b.aligns[b.align]
+
+	listWidth := 0
+	for _, font := range b.fonts {
+		listWidth = max(listWidth, displayWidth(font.name))
+	}
+	listWidth += 2
+	previewWidth := max(1, cols-listWidth-3)
+
+	// Keep the selected banner inside the visible part of the list
+	if b.selected < b.top {
+		b.top = b.selected
+	}
+	if b.selected >= b.top+height {
+		b.top = b.selected - height + 1
+	}
+
+	art, err := b.art(previewWidth, true)
+	if err != nil {
+		art = []string{"", tr("Error:") + " " + err.Error()}
+	}
+
+	var frame strings.Builder
+	frame.WriteString("\033[H")
+	for y := 0; y < height; y++ {
+		entry := ""
+		if i := b.top + y; i < len(b.fonts) {
+			entry = " " + b.fonts[i].name
+			entry += strings.Repeat(" ", listWidth-displayWidth(entry))
+			if i == b.selected {
+				entry = "\033[7m" + entry + "\033[0m"
+			}
+		} else {
+			entry = strings.Repeat(" ", listWidth)
+		}
+		row := ""
+		if y < len(art) {
+			row = truncateWidth(art[y], previewWidth)
+		}
+		frame.WriteString(entry + " | " + row + "\033[K\n")
+	}
+
+	status := fmt.Sprintf(tr(" %s %d/%d  color: %s  align: %s   %s"), b.opts.banner, b.selected+1, len(b.fonts),
+		orNone(b.opts.color), b.opts.align, tr(browserKeys))
+	if b.editing {
+		status = fmt.Sprintf(tr(" text: %s_   (enter to keep, esc to cancel)"), string(b.input))
+	}
+	frame.WriteString("\033[7m" + padRight(truncateWidth(status, cols), cols) + "\033[0m")
+	io.WriteString(w, frame.String())
+}
+
+// commandLine returns the command that renders the final choice: the flags
+// the program was started with, except --browse and the ones the browser
+// chose itself, then the banner, color, alignment and the text.
+func (b *browser) commandLine(raw []string) string {
+	chosen := map[string]bool{"browse": true, "banner": true, "color": true, "align": true, "input": true}
+	specs := (&options{}).flagSpecs()
+	parts := []string{"go", "run", "."}
+	for i := 0; i < len(raw); i++ {
+		arg := raw[i]
+		name, _, inline := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		spec := findFlag(specs, name, strings.HasPrefix(arg, "--"))
+		if spec == nil || arg == "-" || !strings.HasPrefix(arg, "-") {
+			parts = append(parts, shellQuote(arg))
+			continue
+		}
+		hasValue := spec.hasValue && !inline && i+1 < len(raw)
+		if !chosen[spec.name] {
+			parts = append(parts, shellQuote(arg))
+			if hasValue {
+				parts = append(parts, shellQuote(raw[i+1]))
+			}
+		}
+		if hasValue {
+			i++
+		}
+	}
+
+	parts = append(parts, "--banner="+shellQuote(b.opts.banner))
+	if b.opts.color != "" {
+		parts = append(parts, "--color="+b.opts.color)
+	}
+	parts = append(parts, "--align="+b.opts.align)
+	text := strings.ReplaceAll(b.text, "\n", `\n`)
+	if strings.HasPrefix(text, "-") {
+		parts = append(parts, "--")
+	}
+	return strings.Join(append(parts, shellQuote(text)), " ")
+}
+
+// shellSafe matches words that need no quoting in a POSIX shell.
+var shellSafe = regexp.MustCompile(`^[A-Za-z0-9_./:=@%+,-]+$`)
+
+// shellQuote quotes s for a POSIX shell when it needs it.
+func shellQuote(s string) string {
+	if shellSafe.MatchString(s) {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// padRight pads s with spaces to width display columns.
+func padRight(s string, width int) string {
+	return s + strings.Repeat(" ", max(0, width-displayWidth(s)))
+}
+
+// orNone returns s, or "none" when it is empty.
+func orNone(s string) string {
+	if s == "" {
+		return tr("none")
+	}
+	return s
+}
+
+// indexOf returns the position of s in list, or -1.
+func indexOf(list []string, s string) int {
+	for i, item := range list {
+		if item == s {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+)
+
+// canvas is a fixed-size box (e.g. 120x30) the rendered art is placed into.
+// From the outside in: margin, an optional border, padding, then the content
+// area where the art is aligned horizontally and vertically.
+type canvas struct {
+	width, height int
+	valign        string // top, middle or bottom
+	margin        insets
+	padding       insets
+	border        bool
+	clip          bool // cut off art that does not fit instead of failing
+}
+
+// insets holds space on each side of a box, in columns and rows.
+type insets struct {
+	top, right, bottom, left int
+}
+
+// parseCanvasSize parses a size such as "120x30".
+func parseCanvasSize(value string) (int, int, error) {
+	w, h, ok := strings.Cut(value, "x")
+	if !ok {
+		return 0, 0, errorf("invalid canvas size %q, expected WIDTHxHEIGHT", value)
+	}
+	width, errW := strconv.Atoi(w)
+	height, errH := strconv.Atoi(h)
+	if errW != nil || errH != nil || width <= 0 || height <= 0 {
+		return 0, 0, errorf("invalid canvas size %q, expected WIDTHxHEIGHT", value)
+	}
+	return width, height, nil
+}
+
+// parseInsets parses CSS-like shorthand: "N" for all sides, "V,H" for
+// vertical and horizontal, or "T,R,B,L" for each side.
+func parseInsets(value string) (insets, error) {
+	fields := strings.Split(value, ",")
+	n := make([]int, len(fields))
+	for i, field := range fields {
+		v, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil || v < 0 {
+			return insets{}, errorf("invalid spacing %q", value)
+		}
+		n[i] = v
+	}
+	switch len(n) {
+	case 1:
+		return insets{n[0], n[0], n[0], n[0]}, nil
+	case 2:
+		return insets{n[0], n[1], n[0], n[1]}, nil
+	case 4:
+		return insets{n[0], n[1], n[2], n[3]}, nil
+	}
+	return insets{}, errorf("invalid spacing %q, expected N, V,H or T,R,B,L", value)
+}
+
+// contentSize returns the size of the area left for the art.
+func (c canvas) contentSize() (int, int) {
+	frame := 0
+	if c.border {
+		frame = 2
+	}
+	width := c.width - c.margin.left - c.margin.right - c.padding.left - c.padding.right - frame
+	height := c.height - c.margin.top - c.margin.bottom - c.padding.top - c.padding.bottom - frame
+	return width, height
+}
+
+// place lays out the art rows on the canvas and returns exactly c.height
+// rows of exactly c.width columns. Art larger than the content area is
+// clipped when c.clip is set and reported as an error otherwise.
+func (c canvas) place(lines []string, opts layout) ([]string, error) {
+	width, height := c.contentSize()
+	opts.width = width
+	if width <= 0 || height <= 0 {
+		return nil, errorf("canvas %dx%d is too small for its margins and padding", c.width, c.height)
+	}
+
+	artWidth := 0
+	for _, line := range lines {
+		artWidth = max(artWidth, displayWidth(line))
+	}
+	if !c.clip && (artWidth > width || len(lines) > height) {
+		return nil, errorf("art is %dx%d but the canvas only has room for %dx%d", artWidth, len(lines), width, height)
+	}
+	if len(lines) > height {
+		lines = lines[:height]
+	}
+
+	// Vertical alignment decides how many empty rows go above the art
+	top := 0
+	switch c.valign {
+	case "middle":
+		top = (height - len(lines)) / 2
+	case "bottom":
+		top = height - len(lines)
+	}
+
+	content := make([]string, height)
+	for i, line := range lines {
+		line = alignLine(line, opts)
+		line = truncateWidth(line, width)
+		content[top+i] = line
+	}
+
+	// Wrap the content in padding, border and margin, from the inside out
+	rows := make([]string, 0, c.height)
+	inner := width + c.padding.left + c.padding.right
+	blank := func(w int) string { return strings.Repeat(" ", w) }
+	marginL, marginR := blank(c.margin.left), blank(c.margin.right)
+	outer := c.width
+
+	for i := 0; i < c.margin.top; i++ {
+		rows = append(rows, blank(outer))
+	}
+	if c.border {
+		rows = append(rows, marginL+"+"+strings.Repeat("-", inner)+"+"+marginR)
+	}
+	side := ""
+	if c.border {
+		side = "|"
+	}
+	for i := 0; i < c.padding.top; i++ {
+		rows = append(rows, marginL+side+blank(inner)+side+marginR)
+	}
+	for _, line := range content {
+		line = blank(c.padding.left) + line + blank(width-displayWidth(line)) + blank(c.padding.right)
+		rows = append(rows, marginL+side+line+side+marginR)
+	}
+	for i := 0; i < c.padding.bottom; i++ {
+		rows = append(rows, marginL+side+blank(inner)+side+marginR)
+	}
+	if c.border {
+		rows = append(rows, marginL+"+"+strings.Repeat("-", inner)+"+"+marginR)
+	}
+	for i := 0; i < c.margin.bottom; i++ {
+		rows = append(rows, blank(outer))
+	}
+	return rows, nil
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"sort"
+	"strings"
+)
+
+// ANSI color codes, the same palette as the color program.
+const reset = "\033[0m"
+
+var colorCodes = map[string]string{
+	"red":    "\033[31m",
+	"orange": "\033[38;5;208m",
+	"yellow": "\033[33m",
+	"green":  "\033[32m",
+	"blue":   "\033[34m",
+	"indigo": "\033[38;5;54m",
+	"violet": "\033[35m",
+	"purple": "\033[35m",
+	"cyan":   "\033[36m",
+	"white":  "\033[37m",
+}
+
+// colorNames returns the color names in alphabetical order.
+func colorNames() []string {
+	names := make([]string, 0, len(colorCodes))
+	for name := range colorCodes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// colorBanner returns a copy of banner with every glyph row wrapped in the
+// given color code. Alignment measures display width, so the escape
+// sequences do not shift centered or right-aligned text.
+func colorBanner(banner map[rune][]string, code string) map[rune][]string {
+	colored := make(map[rune][]string, len(banner))
+	for char, charLines := range banner {
+		lines := make([]string, len(charLines))
+		for i, line := range charLines {
+			lines[i] = code + line + reset
+		}
+		colored[char] = lines
+	}
+	return colored
+}
+
+// trimTrailing strips trailing whitespace, looking through escape sequences
+// at the end of the line so colored trailing spaces are removed too.
+func trimTrailing(line string) string {
+	var codes []string // escape sequences peeled off the end of the line
+	for {
+		line = strings.TrimRight(line, " \t")
+		if !strings.HasSuffix(line, "m") {
+			break
+		}
+		start := strings.LastIndex(line, "\033[")
+		if start == -1 || strings.Trim(line[start+2:len(line)-1], "0123456789;") != "" {
+			break
+		}
+		codes = append([]string{line[start:]}, codes...)
+		line = line[:start]
+	}
+	return line + strings.Join(codes, "")
+}
@@ -0,0 +1,237 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// configKeys are the settings a config file may hold. The file is shared by
+// every program of the project: each one applies the keys it has a flag of
+// the same name for and skips the rest.
+var configKeys = []string{"banner", "color", "align", "width", "format", "font-path", "unsupported", "placeholder"}
+
+// configEnvPrefix prefixes the environment variables: ASCII_ART_BANNER,
+// ASCII_ART_FONT_PATH and so on.
+const configEnvPrefix = "ASCII_ART_"
+
+// projectConfigName is the project config file, looked up in the current
+// directory and its parents.
+const projectConfigName = ".asciiartrc"
+
+// configLayer is one source of settings.
+type configLayer struct {
+	source string            // where the values come from, for --show-config
+	dir    string            // directory of the config file; font-path is relative to it
+	values map[string]string // values by key
+}
+
+// configEntry is the effective value of a setting and its source.
+type configEntry struct {
+	value  string
+	source string
+}
+
+// userConfigPath returns the user config file: $XDG_CONFIG_HOME/ascii-art/config
+// or ~/.config/ascii-art/config.
+func userConfigPath() string {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "ascii-art", "config")
+}
+
+// projectConfigPath looks for .asciiartrc in the current directory and its
+// parents.
+func projectConfigPath() string {
+	dir, err := os.Getwd()
+	if err != nil {
+		return ""
+	}
+	for {
+		path := filepath.Join(dir, projectConfigName)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// readConfig reads a config file of "key = value" lines; blank lines and lines
+// starting with # are skipped. A missing file is not an error.
+func readConfig(path string) (map[string]string, error) {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errorf("failed to open %s: %v", path, err)
+	}
+	defer file.Close()
+
+	values := make(map[string]string)
+	scanner := bufio.NewScanner(file)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok {
+			return nil, errorf("%s:%d: expected key = value", path, lineNum)
+		}
+		if !isConfigKey(key) {
+			return nil, errorf("%s:%d: unknown setting %q", path, lineNum, key)
+		}
+		values[key] = strings.Trim(strings.TrimSpace(value), `"`)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errorf("failed to read %s: %v", path, err)
+	}
+	return values, nil
+}
+
+// isConfigKey reports whether key is a known setting.
+func isConfigKey(key string) bool {
+	for _, k := range configKeys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// envName returns the environment variable for key: font-path → ASCII_ART_FONT_PATH.
+func envName(key string) string {
+	return configEnvPrefix + strings.ToUpper(strings.ReplaceAll(key, "-", "_"))
+}
+
+// configLayers collects the sources of settings from lowest to highest
+// precedence: user config, project config, environment.
+func configLayers() ([]configLayer, error) {
+	var layers []configLayer
+	for _, file := range []struct{ kind, path string }{
+		{"user config", userConfigPath()},
+		{"project config", projectConfigPath()},
+	} {
+		if file.path == "" {
+			continue
+		}
+		values, err := readConfig(file.path)
+		if err != nil {
+			return nil, err
+		}
+		if values != nil {
+			layers = append(layers, configLayer{
+				source: file.kind + " " + file.path,
+				dir:    filepath.Dir(file.path),
+				values: values,
+			})
+		}
+	}
+	env := configLayer{values: make(map[string]string)}
+	for _, key := range configKeys {
+		if value, ok := os.LookupEnv(envName(key)); ok {
+			env.values[key] = value
+		}
+	}
+	return append(layers, env), nil
+}
+
+// applyConfig applies the layers in order through the flag setters, so values
+// are checked exactly like on the command line. Keys without a flag of the
+// same name are not used by this program and are skipped. The effective value
+// and source of every applied setting is recorded in effective.
+func applyConfig(specs []flagSpec, layers []configLayer, effective map[string]configEntry) error {
+	for _, layer := range layers {
+		for _, key := range configKeys {
+			value, ok := layer.values[key]
+			spec := findFlag(specs, key, true)
+			if !ok || spec == nil {
+				continue
+			}
+			if key == "font-path" && layer.dir != "" {
+				value = resolvePathList(value, layer.dir)
+			}
+			source := layer.source
+			if source == "" {
+				source = "env " + envName(key)
+			}
+			if err := spec.set(value); err != nil {
+				return errorf("%s: invalid value %q for %s: %v", source, value, key, err)
+			}
+			effective[key] = configEntry{value: value, source: source}
+		}
+	}
+	return nil
+}
+
+// trackFlags wraps the setters of flags that are also settings, so --show-config
+// reports values given on the command line.
+func trackFlags(specs []flagSpec, effective map[string]configEntry) {
+	for i := range specs {
+		name, set := specs[i].name, specs[i].set
+		if !isConfigKey(name) {
+			continue
+		}
+		specs[i].set = func(value string) error {
+			effective[name] = configEntry{value: value, source: "flag --" + name}
+			return set(value)
+		}
+	}
+}
+
+// resolvePathList makes the relative directories of a path list relative to dir.
+func resolvePathList(list, dir string) string {
+	dirs := filepath.SplitList(list)
+	for i, d := range dirs {
+		if d != "" && !filepath.IsAbs(d) {
+			dirs[i] = filepath.Join(dir, d)
+		}
+	}
+	return strings.Join(dirs, string(os.PathListSeparator))
+}
+
+// resolveFont looks for a banner file in the font-path directories in order
+// and returns "" when none of them has it.
+func resolveFont(name, fontPath string) string {
+	for _, dir := range filepath.SplitList(fontPath) {
+		if dir == "" {
+			continue
+		}
+		path := filepath.Join(dir, name)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path
+		}
+	}
+	return ""
+}
+
+// showConfig prints the effective settings with their sources.
+func showConfig(w io.Writer, effective map[string]configEntry) {
+	for _, key := range configKeys {
+		if entry, ok := effective[key]; ok {
+			fmt.Fprintf(w, "%-12s = %-16s # %s\n", key, entry.value, entry.source)
+		}
+	}
+}
+
+// isConfigShow reports whether the positional arguments are the config show
+// command, the same as --show-config. Arguments after "--" never are.
+func isConfigShow(raw, args []string) bool {
+	return len(args) == 2 && args[0] == "config" && args[1] == "show" && !slices.Contains(raw, "--")
+}
@@ -0,0 +1,18 @@
+package main
+
+// lineEndOptions controls how line ends of the output are presented.
+type lineEndOptions struct {
+	showEnds     bool // mark the end of every line with $, like `cat -e`
+	trimTrailing bool // strip trailing whitespace from every line
+}
+
+// applyLineEnd applies the line end options to a single output line.
+func applyLineEnd(line string, opts lineEndOptions) string {
+	if opts.trimTrailing {
+		line = trimTrailing(line)
+	}
+	if opts.showEnds {
+		line += "$"
+	}
+	return line
+}
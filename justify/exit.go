@@ -0,0 +1,57 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+// Exit codes, so scripts can tell classes of failure apart.
+const (
+	exitUsage       = 2 // bad arguments or flags
+	exitFontMissing = 3 // the banner file does not exist
+	exitFontInvalid = 4 // the banner file is malformed
+	exitUnsupported = 5 // the text has characters the banner does not
+	exitIO          = 6 // reading input or writing output failed
+)
+
+// exitStatuses describes the exit codes for the man page.
+var exitStatuses = []struct {
+	code    int
+	meaning string
+}{
+	{0, "Success."},
+	{exitUsage, "Invalid arguments or flags."},
+	{exitFontMissing, "The banner was not found."},
+	{exitFontInvalid, "The banner file is malformed."},
+	{exitUnsupported, "The text has characters missing from the banner (with --unsupported=error)."},
+	{exitIO, "A file or stream could not be read or written."},
+}
+
+// errInvalidFont wraps errors about malformed banner files.
+var errInvalidFont = message("invalid banner file")
+
+// fail prints an error to stderr and exits with code.
+func fail(code int, format string, args ...any) {
+	fmt.Fprintln(os.Stderr, tr("Error:"), fmt.Sprintf(tr(format), args...))
+	os.Exit(code)
+}
+
+// usageError prints an error and the usage text to stderr and exits with
+// exitUsage.
+func usageError(format string, args ...any) {
+	fmt.Fprintln(os.Stderr, tr("Error:"), fmt.Sprintf(tr(format), args...))
+	printUsage(os.Stderr)
+	os.Exit(exitUsage)
+}
+
+// fontExitCode picks the exit code for an error from loadBanner.
+func fontExitCode(err error) int {
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return exitFontMissing
+	case errors.Is(err, errInvalidFont):
+		return exitFontInvalid
+	}
+	return exitIO
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// validateFill checks that a fill pattern is non-empty and made of
+// single-column characters, so that it can be measured in columns.
+func validateFill(pattern string) error {
+	if pattern == "" || displayWidth(pattern) != utf8.RuneCountInString(pattern) {
+		return errorf("fill pattern %q must be one or more single-width characters", pattern)
+	}
+	return nil
+}
+
+// fillText returns n columns of the repeating fill pattern. The pattern is
+// anchored at column col, so multi-character patterns such as "-=" line up
+// from row to row no matter where the fill starts.
+func fillText(pattern string, col, n int) string {
+	if n <= 0 {
+		return ""
+	}
+	if pattern == " " {
+		return strings.Repeat(" ", n)
+	}
+	runes := []rune(pattern)
+	var b strings.Builder
+	for i := 0; i < n; i++ {
+		b.WriteRune(runes[(col+i)%len(runes)])
+	}
+	return b.String()
+}
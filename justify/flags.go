@@ -0,0 +1,165 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// flagSpec describes one command-line flag. It is the single source for the
+// help text, the man page and the shell completion scripts. A short alias
+// means the same flag in every program of the suite.
+type flagSpec struct {
+	name     string                   // long name without "--"
+	short    string                   // short alias without "-", may be empty
+	hasValue bool                     // takes a value (--name=value or --name value)
+	arg      string                   // name of the value in the help: N, FILE, NAME
+	usage    string                   // help text; lines are separated by \n
+	values   []string                 // allowed values, offered by completion
+	complete string                   // what else completes the value: completeFont, completeFile, completeDir
+	set      func(value string) error // stores the value; empty for flags without a value
+}
+
+// Kinds of values completed from somewhere other than the values list.
+const (
+	completeFont = "font" // names of the discovered banners
+	completeFile = "file" // a file path
+	completeDir  = "dir"  // a directory
+)
+
+// parseFlags parses flags in any order, mixed with positional arguments.
+// It accepts --name=value, --name value, short aliases -n value and -n=value,
+// and "--" ends the flags: everything after it is positional. A lone "-" is
+// positional too. Errors name the offending argument.
+func parseFlags(args []string, specs []flagSpec) ([]string, error) {
+	var positional []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			return append(positional, args[i+1:]...), nil
+		}
+		if arg == "-" || !strings.HasPrefix(arg, "-") {
+			positional = append(positional, arg)
+			continue
+		}
+
+		name, value, hasInline := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		long := strings.HasPrefix(arg, "--")
+		spec := findFlag(specs, name, long)
+		if spec == nil {
+			return nil, errorf("unknown flag %q (use -- before text that starts with '-')", arg)
+		}
+
+		flagName := "--" + spec.name
+		if !spec.hasValue {
+			if hasInline {
+				return nil, errorf("flag %s does not take a value (got %q)", flagName, arg)
+			}
+		} else if !hasInline {
+			if i+1 >= len(args) {
+				return nil, errorf("flag %s needs a value", flagName)
+			}
+			i++
+			value = args[i]
+		}
+		if err := spec.set(value); err != nil {
+			if !spec.hasValue {
+				return nil, errorf("%s: %v", flagName, err)
+			}
+			return nil, errorf("invalid value %q for %s: %v", value, flagName, err)
+		}
+	}
+	return positional, nil
+}
+
+// findFlag looks a flag up by its long or short name.
+func findFlag(specs []flagSpec, name string, long bool) *flagSpec {
+	for i := range specs {
+		if (long && specs[i].name == name) || (!long && specs[i].short != "" && specs[i].short == name) {
+			return &specs[i]
+		}
+	}
+	return nil
+}
+
+// flagLabel returns a flag as the help shows it: "-w, --width=N".
+func flagLabel(spec flagSpec) string {
+	label := "    --" + spec.name
+	if spec.short != "" {
+		label = "-" + spec.short + ", --" + spec.name
+	}
+	if spec.hasValue {
+		label += "=" + spec.arg
+	}
+	return label
+}
+
+// printFlags prints the flags in two columns, descriptions starting at column.
+func printFlags(w io.Writer, specs []flagSpec, column int) {
+	for _, spec := range specs {
+		printEntry(w, column, flagLabel(spec), spec.usage)
+	}
+}
+
+// printEntry prints one help entry: continuation lines line up with the
+// description, and a label too long for its column gets a line of its own.
+func printEntry(w io.Writer, column int, label, usage string) {
+	lines := strings.Split(tr(usage), "\n")
+	if len(label) >= column {
+		fmt.Fprintf(w, "  %s\n", label)
+		label = ""
+	}
+	fmt.Fprintf(w, "  %-*s%s\n", column, label, lines[0])
+	for _, line := range lines[1:] {
+		fmt.Fprintf(w, "  %-*s%s\n", column, "", line)
+	}
+}
+
+// setTrue returns a handler for a flag without a value that turns *dst on.
+func setTrue(dst *bool) func(string) error {
+	return func(string) error {
+		*dst = true
+		return nil
+	}
+}
+
+// setAction returns the handler of a flag that runs an action instead of
+// rendering text. Actions exclude each other, so only one can be given.
+func setAction(dst *string, name string) func(string) error {
+	return func(string) error {
+		if *dst != "" && *dst != name {
+			return errorf("cannot be combined with --%s", *dst)
+		}
+		*dst = name
+		return nil
+	}
+}
+
+// setInt returns a handler that stores an integer of at least minValue.
+func setInt(dst *int, minValue int) func(string) error {
+	return func(value string) error {
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return errorf("not a number")
+		}
+		if n < minValue {
+			return errorf("must be at least %d", minValue)
+		}
+		*dst = n
+		return nil
+	}
+}
+
+// setOneOf returns a handler that only accepts one of the allowed values.
+func setOneOf(dst *string, allowed ...string) func(string) error {
+	return func(value string) error {
+		for _, a := range allowed {
+			if value == a {
+				*dst = value
+				return nil
+			}
+		}
+		return errorf("must be one of %s", strings.Join(allowed, ", "))
+	}
+}
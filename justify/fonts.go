@@ -0,0 +1,44 @@
+package main
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// fontInfo is a discovered banner: its name for --banner and its file.
+type fontInfo struct {
+	name string
+	path string
+}
+
+// discoverFonts finds the banners that can be used by name: *.txt files in
+// the --font-path directories and in the bundled banner/ directory. When two
+// directories have a banner of the same name the first wins, as it does for
+// --banner; files that do not parse as a banner are skipped.
+func discoverFonts(fontPath string) []fontInfo {
+	var paths []string
+	for _, dir := range append(filepath.SplitList(fontPath), bundledBannerDir) {
+		if dir == "" {
+			continue
+		}
+		matches, _ := filepath.Glob(filepath.Join(dir, "*.txt"))
+		paths = append(paths, matches...)
+	}
+
+	var fonts []fontInfo
+	seen := make(map[string]bool)
+	for _, path := range paths {
+		name := strings.TrimSuffix(filepath.Base(path), ".txt")
+		if seen[name] {
+			continue
+		}
+		if _, err := readBanner(path); err != nil {
+			continue
+		}
+		seen[name] = true
+		fonts = append(fonts, fontInfo{name: name, path: path})
+	}
+	sort.Slice(fonts, func(i, j int) bool { return fonts[i].name < fonts[j].name })
+	return fonts
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"io"
+	"os"
+	"strings"
+)
+
+// stdinName stands for standard input in place of a string or a file name.
+const stdinName = "-"
+
+// readInput reads text from a file, or from standard input when path is "-".
+// Real newlines become line breaks in the banner; the final newline (as printed
+// by `hostname` or `git describe`) is dropped and \r\n is treated as \n.
+func readInput(path string) (string, error) {
+	var data []byte
+	var err error
+	if path == stdinName {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		if path == stdinName {
+			return "", errorf("failed to read stdin: %v", err)
+		}
+		return "", errorf("failed to read %s: %v", path, err)
+	}
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+	return strings.TrimSuffix(text, "\n"), nil
+}
+
+// stdinIsPiped reports whether standard input is redirected from a pipe or a
+// file rather than attached to a terminal.
+func stdinIsPiped() bool {
+	info, err := os.Stdin.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice == 0
+}
+
+// inputText picks where the text comes from: the --input file, standard input
+// (a "-" argument, or no string at all while input is piped) or the first
+// argument. It returns the text, the remaining arguments and false when there
+// is no text.
+func inputText(inputFile string, args []string) (string, []string, bool, error) {
+	switch {
+	case inputFile != "":
+		text, err := readInput(inputFile)
+		return text, args, true, err
+	case len(args) > 0 && args[0] == stdinName:
+		text, err := readInput(stdinName)
+		return text, args[1:], true, err
+	case len(args) == 0 && stdinIsPiped():
+		text, err := readInput(stdinName)
+		return text, args, true, err
+	case len(args) == 0:
+		return "", args, false, nil
+	}
+	return expandNewlines(args[0]), args[1:], true, nil
+}
+
+// expandNewlines turns the \n sequences of a command-line argument into
+// newlines. Text from stdin and files comes with real newlines and is used
+// as is, so the backslashes in it are kept.
+func expandNewlines(arg string) string {
+	return strings.ReplaceAll(arg, `\n`, "\n")
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// testBanner is a two-row banner with one-column glyphs, so the gaps of a
+// justified line can be read straight from the result: the space glyph,
+// then the "." fill for the extra columns.
+var testBanner = map[rune][]string{
+	' ': {" ", " "},
+	'a': {"a", "A"},
+	'b': {"b", "B"},
+	'c': {"c", "C"},
+	'd': {"d", "D"},
+}
+
+func TestJustifyGaps(t *testing.T) {
+	tests := []struct {
+		name, text string
+		width      int
+		want       string
+	}{
+		{"even gaps", "a b c", 9, "a ..b ..c"},
+		{"remainder goes to the first gaps", "a b c d", 12, "a ..b ..c .d"},
+		{"one extra column", "a b c", 6, "a .b c"},
+		{"words stay together", "ab cd", 9, "ab ....cd"},
+		{"repeated spaces are one gap", "a   b", 5, "a ..b"},
+		{"already full", "a b", 3, "a b"},
+		{"too wide", "ab cd", 4, "ab cd"},
+		{"single word", "abc", 9, "abc"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows := generateArtLine(tt.text, testBanner, "justify", tt.width, ".")
+			if got := rows[0]; got != tt.want {
+				t.Errorf("generateArtLine(%q, width %d) = %q, want %q", tt.text, tt.width, got, tt.want)
+			}
+			// Every row gets the same gaps
+			if want := strings.ToUpper(tt.want); rows[1] != want {
+				t.Errorf("second row = %q, want %q", rows[1], want)
+			}
+		})
+	}
+}
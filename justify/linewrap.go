@@ -0,0 +1,74 @@
+package main
+
+import "unicode/utf8"
+
+// wrapText splits a line of text into parts whose rendered width fits in
+// width columns. Breaks happen at spaces between words; a word wider than
+// width is broken between characters. Spaces at a break are dropped.
+// A width of 0 or less disables wrapping.
+func wrapText(line string, width int, glyphWidth func(rune) int) []string {
+	textWidth := func(text string) int {
+		w := 0
+		for _, r := range text {
+			w += glyphWidth(r)
+		}
+		return w
+	}
+	if width <= 0 || textWidth(line) <= width {
+		return []string{line}
+	}
+
+	var parts []string
+	start, end := 0, 0 // current part; leading spaces of the first part are kept
+	curWidth := 0
+	for i := 0; i < len(line); {
+		if line[i] == ' ' {
+			i++
+			continue
+		}
+		j := i
+		for j < len(line) && line[j] != ' ' {
+			j++
+		}
+		wordWidth := textWidth(line[i:j])
+
+		// Try to append the word, together with the spaces before it
+		gap := textWidth(line[end:i])
+		if curWidth+gap+wordWidth <= width {
+			end = j
+			curWidth += gap + wordWidth
+			i = j
+			continue
+		}
+		if end > start {
+			parts = append(parts, line[start:end])
+		}
+
+		// The word starts a new part; break it up if it is too wide on its own
+		for wordWidth > width {
+			k, w := i, 0
+			for k < j {
+				r, size := utf8.DecodeRuneInString(line[k:])
+				gw := glyphWidth(r)
+				if w+gw > width && k > i {
+					break
+				}
+				w += gw
+				k += size
+			}
+			parts = append(parts, line[i:k])
+			i = k
+			wordWidth -= w
+		}
+		start, end = i, j
+		curWidth = wordWidth
+		i = j
+	}
+	if end > start {
+		parts = append(parts, line[start:end])
+	}
+	if len(parts) == 0 {
+		return []string{line}
+	}
+	return parts
+}
@@ -2,63 +2,374 @@ package main
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 )
 
-const defaultWidth = 200 // Increased default width to handle longer strings
+const defaultWidth = 200 // Fallback width when neither the terminal nor $COLUMNS tell us
+
+const bannerHeight = 8 // Rows in every glyph of a banner
+
+const bundledBannerDir = "banner" // Banners shipped with the program
 
 func main() {
-	if len(os.Args) < 2 {
-		printUsage()
+	// The message language is picked before the flags are parsed (see messages.go)
+	lang = detectLang(os.Args[1:])
+	// Flags may come in any order, before or after the text
+	// Diagnostics go to stderr with an exit code per class of error (see exit.go)
+	opts, args, err := parseOptions(os.Args[1:])
+	if err != nil {
+		usageError("%v", err)
+	}
+	if opts.help {
+		printUsage(os.Stdout)
 		return
 	}
-
-	// Parse alignment flag
-	align := "left" // default alignment
-	text := ""
-	bannerName := "standard" // default banner
-
-	if strings.HasPrefix(os.Args[1], "--align=") {
-		align = strings.TrimPrefix(os.Args[1], "--align=")
-		if !isValidAlignment(align) {
-			printUsage()
-			return
+	if opts.configErr != nil {
+		usageError("%v", opts.configErr)
+	}
+	// config show is the same as --show-config
+	if opts.action == "" && isConfigShow(os.Args[1:], args) {
+		opts.action, args = "show-config", nil
+	}
+	// Action flags run instead of rendering and take no text
+	if opts.action != "" && len(args) > 0 {
+		usageError("unexpected argument %q", args[0])
+	}
+	switch opts.action {
+	case "repl":
+		if err := runREPL(os.Stdin, os.Stdout, os.Stderr, opts, !stdinIsPiped()); err != nil {
+			fail(fontExitCode(err), "%v", err)
 		}
-		if len(os.Args) < 3 {
-			printUsage()
-			return
+		return
+	case "browse":
+		line, err := runBrowser(opts, os.Args[1:])
+		if errors.Is(err, errNoTerminal) {
+			fail(exitUsage, "%v", err)
+		} else if err != nil {
+			fail(fontExitCode(err), "%v", err)
 		}
-		text = os.Args[2]
-		if len(os.Args) > 3 {
-			bannerName = os.Args[3]
+		if line != "" {
+			fmt.Println(line)
 		}
-	} else {
-		text = os.Args[1]
-		if len(os.Args) > 2 {
-			bannerName = os.Args[2]
+		return
+	case "show-config":
+		showConfig(os.Stdout, opts.settings)
+		return
+	case "completion":
+		writeCompletion(os.Stdout, opts.shell, programName(), (&options{}).flagSpecs())
+		return
+	case "man":
+		writeManPage(os.Stdout, programName(), (&options{}).flagSpecs())
+		return
+	case "list-fonts":
+		for _, font := range discoverFonts(opts.fontPath) {
+			fmt.Println(font.name)
 		}
+		return
 	}
+	// The text comes from the first argument, the --input file or stdin
+	text, args, ok, err := inputText(opts.input, args)
+	if err != nil {
+		fail(exitIO, "%v", err)
+	}
+	if !ok {
+		printUsage(os.Stderr)
+		os.Exit(exitUsage)
+	}
+	if len(args) > 1 {
+		usageError("unexpected argument %q", args[1])
+	}
+	bannerName := opts.defaultBanner
+	if opts.banner != "" {
+		bannerName = opts.banner
+	}
+	if len(args) == 1 {
+		if opts.banner != "" {
+			usageError("banner given twice: --banner=%s and %q", opts.banner, args[0])
+		}
+		bannerName = args[0]
+	}
+	ends, width, box := opts.ends, opts.width, opts.canvas
 
 	// Load banner
-	banner, err := loadBanner(bannerName)
+	banner, err := loadBanner(bannerName, opts.fontPath)
 	if err != nil {
-		fmt.Println("Error loading banner:", err)
+		fail(fontExitCode(err), "loading banner: %v", err)
+	}
+	// Characters missing from the banner are handled by the --unsupported policy
+	if text, err = applyCharPolicy(text, banner, opts.chars); err != nil {
+		fail(exitUnsupported, "%v", err)
+	}
+	if opts.color != "" {
+		banner = colorBanner(banner, colorCodes[opts.color])
+	}
+
+	lay := opts.layout()
+
+	// Place the art on a fixed-size canvas
+	if box != nil {
+		rows, err := renderArt(text, banner, lay, box, ends)
+		if err != nil {
+			fail(exitUsage, "%v", err)
+		}
+		for _, row := range rows {
+			fmt.Println(row)
+		}
 		return
 	}
 
 	// Generate and print ASCII art
-	asciiArt := generateAsciiArt(text, banner, align, defaultWidth)
-	printAligned(asciiArt, align)
+	render := func() {
+		lay.width = outputWidth(width)
+		rows, _ := renderArt(text, banner, lay, nil, ends)
+		for _, row := range rows {
+			fmt.Println(row)
+		}
+	}
+	if opts.watch && width == 0 && isTerminal() {
+		// Redraw on every terminal resize until interrupted
+		watchResize(render)
+		return
+	}
+	render()
+}
+
+// options holds the parsed command-line flags.
+type options struct {
+	align         string // empty unless --align is given
+	width         int    // 0 means detect from the terminal
+	noWrap        bool
+	watch         bool
+	color         string
+	direction     string
+	fill          string
+	padLeft       int
+	padRight      int
+	canvas        *canvas // nil unless --canvas is given
+	box           canvas  // settings for the canvas, used once --canvas is given
+	banner        string
+	input         string                 // file to read the text from, "-" for stdin
+	chars         charPolicy             // what to do with characters missing from the banner
+	fontPath      string                 // directories searched for banners, separated like $PATH
+	defaultBanner string                 // from the settings, unless given as an argument or with --banner
+	settings      map[string]configEntry // effective settings, for --show-config
+	configErr     error                  // error in the config files or ASCII_ART_*
+	action        string                 // flag that replaces rendering, such as repl
+	shell         string                 // shell for --completion
+	ends          lineEndOptions
+	help          bool
+}
+
+// layout returns the layout the flags ask for, without the width.
+// Right-to-left text reads naturally against the right edge, so it is
+// right-aligned unless --align says otherwise.
+func (opts options) layout() layout {
+	align := opts.align
+	if align == "" {
+		align = "left" // default alignment
+		if opts.direction == directionRTL {
+			align = "right"
+		}
+	}
+	return layout{align: align, wrap: !opts.noWrap, direction: opts.direction, fill: opts.fill, padLeft: opts.padLeft, padRight: opts.padRight}
+}
+
+// flagSpecs describes the flags; their handlers store the values in opts.
+func (opts *options) flagSpecs() []flagSpec {
+	aligns := []string{"left", "right", "center", "justify"}
+	directions := []string{directionLTR, directionRTL, directionAuto}
+	valigns := []string{"top", "middle", "bottom"}
+	overflows := []string{"clip", "error"}
+	policies := []string{policySkip, policyError, policyPlaceholder, policyTranslit}
+	colors := colorNames()
+	return []flagSpec{
+		{name: "align", hasValue: true, arg: "A", values: aligns,
+			usage: "left, right, center or justify", set: setOneOf(&opts.align, aligns...)},
+		{name: "width", short: "w", hasValue: true, arg: "N",
+			usage: "align to N columns instead of the terminal width", set: setInt(&opts.width, 1)},
+		{name: "no-wrap", usage: "do not wrap text wider than the width", set: setTrue(&opts.noWrap)},
+		{name: "color", short: "c", hasValue: true, arg: "COLOR", values: colors,
+			usage: "color the art",
+			set: func(value string) error {
+				return setOneOf(&opts.color, colors...)(strings.ToLower(value))
+			}},
+		{name: "rtl", usage: "right-to-left text (same as --direction=rtl)",
+			set: func(string) error {
+				opts.direction = directionRTL
+				return nil
+			}},
+		{name: "direction", hasValue: true, arg: "D", values: directions,
+			usage: "ltr, rtl or auto", set: setOneOf(&opts.direction, directions...)},
+		{name: "fill", hasValue: true, arg: "PATTERN",
+			usage: "fill alignment padding and justify gaps with PATTERN",
+			set: func(value string) error {
+				opts.fill = value
+				return validateFill(value)
+			}},
+		{name: "pad-left", hasValue: true, arg: "N", usage: "columns of fill always kept left of the art", set: setInt(&opts.padLeft, 0)},
+		{name: "pad-right", hasValue: true, arg: "N", usage: "columns of fill always kept right of the art", set: setInt(&opts.padRight, 0)},
+		{name: "canvas", hasValue: true, arg: "WxH", usage: "place the art in a fixed-size box",
+			set: func(value string) (err error) {
+				opts.box.width, opts.box.height, err = parseCanvasSize(value)
+				opts.canvas = &opts.box
+				return err
+			}},
+		{name: "valign", hasValue: true, arg: "V", values: valigns,
+			usage: "top, middle or bottom within the canvas", set: setOneOf(&opts.box.valign, valigns...)},
+		{name: "margin", hasValue: true, arg: "N",
+			usage: "space around the canvas border: N, V,H or T,R,B,L",
+			set: func(value string) (err error) {
+				opts.box.margin, err = parseInsets(value)
+				return err
+			}},
+		{name: "padding", hasValue: true, arg: "N",
+			usage: "space inside the canvas border: N, V,H or T,R,B,L",
+			set: func(value string) (err error) {
+				opts.box.padding, err = parseInsets(value)
+				return err
+			}},
+		{name: "border", usage: "draw a border around the canvas", set: setTrue(&opts.box.border)},
+		{name: "overflow", hasValue: true, arg: "MODE", values: overflows,
+			usage: "clip art larger than the canvas, or fail with an error (default)",
+			set: func(value string) error {
+				overflow := ""
+				err := setOneOf(&overflow, overflows...)(value)
+				opts.box.clip = overflow == "clip"
+				return err
+			}},
+		{name: "banner", short: "b", hasValue: true, arg: "NAME", complete: completeFont,
+			usage: "banner to use (default standard)",
+			set: func(value string) error {
+				opts.banner = value
+				return nil
+			}},
+		{name: "input", short: "i", hasValue: true, arg: "FILE", complete: completeFile,
+			usage: "read the text from FILE ('-' is stdin); without a STRING\nthe text is read from stdin when it is piped",
+			set: func(value string) error {
+				if value == "" {
+					return errorf("missing file name")
+				}
+				opts.input = value
+				return nil
+			}},
+		{name: "watch", usage: "redraw whenever the terminal is resized", set: setTrue(&opts.watch)},
+		{name: "unsupported", short: "u", hasValue: true, arg: "MODE", values: policies,
+			usage: "characters missing from the banner: skip (default), error,\nplaceholder or translit (é→e, ß→ss, curly quotes→straight)",
+			set:   setOneOf(&opts.chars.mode, policies...)},
+		{name: "placeholder", hasValue: true, arg: "CHAR",
+			usage: "draw CHAR (or 'box') for missing characters (default '?')",
+			set: func(value string) error {
+				opts.chars.mode = policyPlaceholder
+				opts.chars.placeholder = value
+				return validatePlaceholder(value)
+			}},
+		{name: "font-path", hasValue: true, arg: "DIRS", complete: completeDir,
+			usage: "directories searched for banner files first (':'-separated)",
+			set: func(value string) error {
+				opts.fontPath = value
+				return nil
+			}},
+		{name: "show-ends", short: "E", usage: "mark the end of every line with $", set: setTrue(&opts.ends.showEnds)},
+		{name: "trim-trailing", short: "T", usage: "strip trailing spaces", set: setTrue(&opts.ends.trimTrailing)},
+		{name: "repl", usage: "type text and see it rendered at once; :help lists the commands",
+			set: setAction(&opts.action, "repl")},
+		{name: "browse", usage: "pick a font, color and alignment in a full-screen preview",
+			set: setAction(&opts.action, "browse")},
+		{name: "show-config", usage: "print the effective settings and where they come from",
+			set: setAction(&opts.action, "show-config")},
+		{name: "completion", hasValue: true, arg: "SHELL", values: completionShells,
+			usage: "print a completion script for bash, zsh or fish",
+			set: func(value string) error {
+				if err := setOneOf(&opts.shell, completionShells...)(value); err != nil {
+					return err
+				}
+				return setAction(&opts.action, "completion")(value)
+			}},
+		{name: "man", usage: "print the manual page in roff format", set: setAction(&opts.action, "man")},
+		{name: "list-fonts", usage: "list the fonts found in the font path and the bundled ones",
+			set: setAction(&opts.action, "list-fonts")},
+		{name: "lang", hasValue: true, arg: "LANG", values: languages,
+			usage: "language of help and messages: en or ru (default: from LC_ALL or LANG)",
+			set:   setOneOf(&lang, languages...)},
+		{name: "help", short: "h", usage: "show this help", set: setTrue(&opts.help)},
+	}
+}
+
+// parseOptions parses the command-line arguments (without the program name)
+// into flags and positional arguments.
+func parseOptions(args []string) (options, []string, error) {
+	opts := options{direction: directionLTR, fill: " ", chars: charPolicy{mode: policySkip, placeholder: "?"}}
+	opts.box = canvas{valign: "top"}
+	specs := opts.flagSpecs()
+
+	// Settings apply from lowest to highest precedence: built-ins, user config,
+	// project config, environment and finally the command line
+	opts.settings = map[string]configEntry{
+		"banner":      {"standard", "built-in"},
+		"color":       {"", "built-in"},
+		"align":       {"left (right for rtl)", "built-in"},
+		"width":       {"terminal", "built-in"},
+		"font-path":   {"", "built-in"},
+		"unsupported": {policySkip, "built-in"},
+		"placeholder": {"?", "built-in"},
+	}
+	layers, err := configLayers()
+	opts.banner = "standard"
+	if err == nil {
+		err = applyConfig(specs, layers, opts.settings)
+	}
+	// A broken config is reported by main after --help, so the help still
+	// shows with a malformed .asciiartrc
+	opts.configErr = err
+	opts.defaultBanner, opts.banner = opts.banner, ""
+
+	trackFlags(specs, opts.settings)
+	positional, err := parseFlags(args, specs)
+	if opts.chars.mode != opts.settings["unsupported"].value {
+		// --placeholder switches to the placeholder policy
+		opts.settings["unsupported"] = configEntry{opts.chars.mode, opts.settings["placeholder"].source}
+	}
+	return opts, positional, err
+}
+
+// layout holds how the text is laid out: alignment, target width, whether
+// long lines wrap, the text direction and what fills the space around it.
+type layout struct {
+	align     string
+	width     int
+	wrap      bool
+	direction string
+	fill      string // pattern used for alignment padding and justify gaps
+	padLeft   int    // columns of fill always kept left of the art
+	padRight  int    // columns of fill always kept right of the art
+}
+
+// innerWidth is the width available to the art itself, inside the padding.
+func (l layout) innerWidth() int {
+	return l.width - l.padLeft - l.padRight
+}
+
+// loadBanner reads a banner from the first --font-path directory that has
+// it, falling back to the bundled banner/ directory.
+func loadBanner(name, fontPath string) (map[rune][]string, error) {
+	return readBanner(bannerFile(name, fontPath))
 }
 
-func isValidAlignment(align string) bool {
-	return align == "left" || align == "right" || align == "center" || align == "justify"
+// bannerFile returns the file of the banner called name: the first match in
+// fontPath, or else the bundled one.
+func bannerFile(name, fontPath string) string {
+	if filename := resolveFont(name+".txt", fontPath); filename != "" {
+		return filename
+	}
+	return fmt.Sprintf("%s/%s.txt", bundledBannerDir, name)
 }
 
-func loadBanner(name string) (map[rune][]string, error) {
-	filename := fmt.Sprintf("banner/%s.txt", name)
+// readBanner reads and validates a banner file.
+func readBanner(filename string) (map[rune][]string, error) {
 	file, err := os.Open(filename)
 	if err != nil {
 		return nil, err
@@ -74,6 +385,9 @@ func loadBanner(name string) (map[rune][]string, error) {
 		line := scanner.Text()
 		if len(line) == 0 {
 			if len(currentLines) > 0 {
+				if len(currentLines) != bannerHeight {
+					return nil, glyphHeightError(filename, currentChar, len(currentLines))
+				}
 				banner[currentChar] = currentLines
 				currentLines = []string{}
 				currentChar++
@@ -84,145 +398,236 @@ func loadBanner(name string) (map[rune][]string, error) {
 	}
 
 	if len(currentLines) > 0 {
+		if len(currentLines) != bannerHeight {
+			return nil, glyphHeightError(filename, currentChar, len(currentLines))
+		}
 		banner[currentChar] = currentLines
 	}
+	if err := scanner.Err(); err != nil {
+		return nil, errorf("failed to read %s: %w", filename, err)
+	}
+	// A banner covers every printable ASCII character, space through tilde
+	if len(banner) < '~'-' '+1 {
+		return nil, errorf("%w %s: %d of %d characters", errInvalidFont, filename, len(banner), '~'-' '+1)
+	}
 
 	return banner, nil
 }
 
-func generateAsciiArt(text string, banner map[rune][]string, align string, width int) []string {
+// glyphHeightError reports a glyph that does not have bannerHeight rows.
+func glyphHeightError(filename string, char rune, rows int) error {
+	return errorf("%w %s: character %q has %d lines instead of %d", errInvalidFont, filename, char, rows, bannerHeight)
+}
+
+// generateAsciiArt renders text line by line. Lines are separated by real
+// newlines (a \n typed in an argument is turned into one by expandNewlines)
+// and each one becomes its own block of glyph rows, justified independently;
+// an empty line stays a single empty row, the same way RenderText treats it
+// in the other programs.
+// With wrap set, lines wider than width are wrapped at word boundaries.
+func generateAsciiArt(text string, banner map[rune][]string, opts layout) []string {
+	align, width := opts.align, opts.innerWidth()
 	if text == "" {
 		return []string{}
 	}
+	if text == "\n" {
+		return []string{""}
+	}
 
-	height := len(banner[' ']) // Assuming all characters have same height
-	result := make([]string, height)
-
-	// If not justifying, generate as before
-	if align != "justify" {
-		for _, char := range text {
-			charLines, exists := banner[char]
-			if !exists {
-				charLines = banner[' '] // Use space for unknown characters
-			}
-
-			for i := 0; i < height; i++ {
-				result[i] += charLines[i]
+	var result []string
+	for _, line := range strings.Split(text, "\n") {
+		if line == "" {
+			result = append(result, "")
+			continue
+		}
+		// Wrap lines that do not fit; the last part of a wrapped paragraph
+		// is left as is rather than stretched, like in a justified text
+		parts := []string{line}
+		if opts.wrap {
+			parts = wrapText(line, width, func(r rune) int { return glyphWidth(banner, r) })
+		}
+		for i, part := range parts {
+			// Wrapping works in reading order; each wrapped line is then
+			// put into the order its glyphs are drawn in
+			part = visualOrder(part, opts.direction)
+			partAlign := align
+			if align == "justify" && len(parts) > 1 && i == len(parts)-1 {
+				partAlign = "left"
 			}
+			result = append(result, generateArtLine(part, banner, partAlign, width, opts.fill)...)
 		}
-		return result
 	}
+	return result
+}
 
-	// For justify: calculate spacing between characters
-	// First, collect all character blocks
-	var charBlocks [][]string
-	for _, char := range text {
-		charLines, exists := banner[char]
-		if !exists {
-			charLines = banner[' '] // Use space for unknown characters
-		}
-		charBlocks = append(charBlocks, charLines)
+// glyphWidth returns the rendered width of r; unknown characters render as a space.
+func glyphWidth(banner map[rune][]string, r rune) int {
+	if charLines, exists := banner[r]; exists && len(charLines) > 0 {
+		return displayWidth(charLines[0])
 	}
+	if charLines := banner[' ']; len(charLines) > 0 {
+		return displayWidth(charLines[0])
+	}
+	return 0
+}
 
-	// Calculate total width of all blocks without extra spacing
-	blockWidth := 0
-	if len(charBlocks) > 0 {
-		blockWidth = len(charBlocks[0][0]) // Width of one character block
+// generateArtLine renders a single line of text, justifying it if requested.
+func generateArtLine(text string, banner map[rune][]string, align string, width int, fill string) []string {
+	height := len(banner[' ']) // Assuming all characters have same height
+
+	// If not justifying, generate as before
+	if align != "justify" {
+		return renderBlock(text, banner, height)
 	}
-	totalBlockWidth := blockWidth * len(charBlocks)
 
-	// If the total width is already >= target width, no need to justify
-	if totalBlockWidth >= width || len(charBlocks) <= 1 {
-		for _, charLines := range charBlocks {
-			for i := 0; i < height; i++ {
-				result[i] += charLines[i]
-			}
-		}
-		return result
+	// For justify: widen only the gaps between words, so a line of
+	// several words spans the full width while letters stay together
+	words := strings.Fields(text)
+	if len(words) <= 1 {
+		// Nothing to spread a single word across, fall back to left alignment
+		return renderBlock(text, banner, height)
 	}
 
-	// Calculate spaces to distribute
-	extraSpaces := width - totalBlockWidth
-	gaps := len(charBlocks) - 1
-	if gaps <= 0 {
-		for _, charLines := range charBlocks {
-			for i := 0; i < height; i++ {
-				result[i] += charLines[i]
-			}
-		}
-		return result
+	// Render every word and measure the total width with one space glyph per gap
+	wordBlocks := make([][]string, len(words))
+	spaceBlock := renderBlock(" ", banner, height)
+	totalWidth := 0
+	for i, word := range words {
+		wordBlocks[i] = renderBlock(word, banner, height)
+		totalWidth += displayWidth(wordBlocks[i][0])
 	}
+	gaps := len(words) - 1
+	totalWidth += gaps * displayWidth(spaceBlock[0])
 
+	// If the words already fill the width, no need to justify
+	if totalWidth >= width {
+		return renderBlock(text, banner, height)
+	}
+
+	// Distribute the remaining columns evenly, giving the first gaps one extra
+	// column each until the remainder is used up
+	extraSpaces := width - totalWidth
 	spacesPerGap := extraSpaces / gaps
-	extraSpacesForLastGaps := extraSpaces % gaps
+	remainder := extraSpaces % gaps
 
-	// Build the justified ASCII art
-	for charIdx, charLines := range charBlocks {
+	result := make([]string, height)
+	for wordIdx, block := range wordBlocks {
 		for i := 0; i < height; i++ {
-			result[i] += charLines[i]
+			result[i] += block[i]
 		}
-
-		// Add spaces after this character, unless it's the last one
-		if charIdx < len(charBlocks)-1 {
-			spaceCount := spacesPerGap
-			if charIdx < extraSpacesForLastGaps {
-				spaceCount++
-			}
-			spacePadding := strings.Repeat(" ", spaceCount)
-			for i := 0; i < height; i++ {
-				result[i] += spacePadding
-			}
+		if wordIdx == gaps {
+			break
+		}
+		spaceCount := spacesPerGap
+		if wordIdx < remainder {
+			spaceCount++
+		}
+		col := displayWidth(result[0]) + displayWidth(spaceBlock[0])
+		padding := fillText(fill, col, spaceCount)
+		for i := 0; i < height; i++ {
+			result[i] += spaceBlock[i] + padding
 		}
 	}
 
 	return result
 }
 
-func printAligned(lines []string, align string) {
-	if len(lines) == 0 {
-		return
+// renderBlock concatenates the glyphs of text row by row.
+func renderBlock(text string, banner map[rune][]string, height int) []string {
+	result := make([]string, height)
+	for _, char := range text {
+		charLines, exists := banner[char]
+		if !exists {
+			charLines = banner[' '] // Use space for unknown characters
+		}
+
+		for i := 0; i < height; i++ {
+			result[i] += charLines[i]
+		}
 	}
+	return result
+}
 
+// alignLines aligns every row of the art within opts.width, widening it
+// to fit the art when needed.
+func alignLines(lines []string, opts layout) []string {
 	// Get maximum line length
 	maxLen := 0
 	for _, line := range lines {
-		if len(line) > maxLen {
-			maxLen = len(line)
+		if displayWidth(line) > maxLen {
+			maxLen = displayWidth(line)
 		}
 	}
 
-	// Use the larger of defaultWidth and maxLen
-	width := defaultWidth
-	if maxLen > width {
-		width = maxLen
+	// Use the larger of the terminal width and maxLen plus padding
+	opts.width = max(opts.width, maxLen+opts.padLeft+opts.padRight)
+
+	aligned := make([]string, len(lines))
+	for i, line := range lines {
+		aligned[i] = alignLine(line, opts)
+	}
+	return aligned
+}
+
+// renderArt renders the text and returns the output rows with the line end
+// options applied: placed on the canvas when box is set, otherwise aligned
+// to lay.width.
+func renderArt(text string, banner map[rune][]string, lay layout, box *canvas, ends lineEndOptions) ([]string, error) {
+	var rows []string
+	if box != nil {
+		lay.width, _ = box.contentSize()
+		var err error
+		if rows, err = box.place(generateAsciiArt(text, banner, lay), lay); err != nil {
+			return nil, err
+		}
+	} else {
+		rows = alignLines(generateAsciiArt(text, banner, lay), lay)
+	}
+	for i, row := range rows {
+		rows[i] = applyLineEnd(row, ends)
 	}
+	return rows, nil
+}
 
-	for _, line := range lines {
-		switch align {
+// alignLine pads a single row with the fill pattern so that it is aligned
+// within opts.width columns, keeping opts.padLeft and opts.padRight columns
+// of fill on either side. Spaces after the art are left out unless right
+// padding is requested. Empty rows between text blocks stay empty.
+func alignLine(line string, opts layout) string {
+	if line == "" {
+		return line
+	}
+	inner := opts.innerWidth()
+	lineWidth := displayWidth(line) // escape sequences and wide glyphs measured in columns
+	left, right := 0, 0
+	if lineWidth < inner {
+		switch opts.align {
 		case "right":
-			if len(line) < width {
-				padding := strings.Repeat(" ", width-len(line))
-				fmt.Println(padding + line)
-			} else {
-				fmt.Println(line)
-			}
+			left = inner - lineWidth
 		case "center":
-			if len(line) < width {
-				padding := strings.Repeat(" ", (width-len(line))/2)
-				fmt.Println(padding + line)
-			} else {
-				fmt.Println(line)
-			}
-		case "justify":
-			// Justification already handled in generateAsciiArt
-			fmt.Println(line)
-		default: // left alignment
-			fmt.Println(line)
+			left = (inner - lineWidth) / 2
+			right = inner - lineWidth - left
+		default: // left alignment; justification is already handled in generateAsciiArt
+			right = inner - lineWidth
 		}
 	}
+	if opts.fill == " " && opts.padRight == 0 {
+		right = 0
+	}
+	left += opts.padLeft
+	return fillText(opts.fill, 0, left) + line + fillText(opts.fill, left+lineWidth, right+opts.padRight)
 }
 
-func printUsage() {
-	fmt.Println("Usage: go run . [OPTION] [STRING] [BANNER]")
-	fmt.Println("\nExample: go run . --align=right something standard")
+// usageSynopses are the program's arguments, for the usage line and the man page.
+var usageSynopses = []string{"[OPTION]... [STRING|-] [BANNER]"}
+
+// usageColumn is where flag descriptions start in the help text.
+const usageColumn = 21
+
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, tr("Usage:"), "go run . "+usageSynopses[0])
+	fmt.Fprintln(w, "\n"+tr("Options (in any order, --flag=value or --flag value; -- ends options):"))
+	printFlags(w, (&options{}).flagSpecs(), usageColumn)
+	fmt.Fprintln(w, "\n"+tr("Example:"), "go run . --align=right something standard")
+	fmt.Fprintln(w, "\n"+tr("Defaults for banner, color, align, width, font-path, unsupported and placeholder are\nread from $XDG_CONFIG_HOME/ascii-art/config, .asciiartrc and ASCII_ART_* variables."))
 }
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+)
+
+// languages are the languages of the messages; English is the one in the code.
+var languages = []string{"en", "ru"}
+
+// lang is the language of help, warnings and errors. main picks it from
+// --lang or the locale variables before anything is printed, and the --lang
+// flag then validates the value.
+var lang = "en"
+
+// detectLang picks the message language before the flags are parsed, so
+// errors in the flags themselves are translated too: --lang first, then
+// LC_ALL, LC_MESSAGES and LANG in POSIX order.
+func detectLang(args []string) string {
+	for i, arg := range args {
+		if arg == "--" {
+			break
+		}
+		value, ok := strings.CutPrefix(arg, "--lang=")
+		if !ok && arg == "--lang" && i+1 < len(args) {
+			value, ok = args[i+1], true
+		}
+		if ok && slices.Contains(languages, value) {
+			return value
+		}
+	}
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if locale := os.Getenv(name); locale != "" {
+			if strings.HasPrefix(strings.ToLower(locale), "ru") {
+				return "ru"
+			}
+			return "en"
+		}
+	}
+	return "en"
+}
+
+// tr returns s in the current language; strings without a translation stay
+// in English.
+func tr(s string) string {
+	if lang == "ru" {
+		if translated, ok := russian[s]; ok {
+			return translated
+		}
+	}
+	return s
+}
+
+// errorf is fmt.Errorf with a translated format.
+func errorf(format string, args ...any) error {
+	return fmt.Errorf(tr(format), args...)
+}
+
+// message is a constant error that is translated when printed, so it can be
+// declared before the language is known and still compared with errors.Is.
+type message string
+
+func (m message) Error() string { return tr(string(m)) }
+
+// russian translates the messages to Russian, keyed by the English text.
+var russian = map[string]string{
+	// browse.go
+	"browse needs an interactive terminal": "для browse нужен интерактивный терминал",
+	"no banners found":                     "баннеры не найдены",
+	"Error:":                               "Ошибка:",
+	" %s %d/%d  color: %s  align: %s   %s": " %s %d/%d  цвет: %s  выравнивание: %s   %s",
+	"up/down font  left/right color  a align  t text  enter done  esc quit": "вверх/вниз шрифт  влево/вправо цвет  a выравнивание  t текст  enter готово  esc выход",
+	" text: %s_   (enter to keep, esc to cancel)":                           " текст: %s_   (enter — сохранить, esc — отменить)",
+	"none": "нет",
+
+	// canvas.go
+	"invalid canvas size %q, expected WIDTHxHEIGHT":         "неверный размер холста %q, ожидается WIDTHxHEIGHT",
+	"invalid spacing %q":                                    "неверные отступы %q",
+	"invalid spacing %q, expected N, V,H or T,R,B,L":        "неверные отступы %q, ожидается N, V,H или T,R,B,L",
+	"canvas %dx%d is too small for its margins and padding": "холст %dx%d слишком мал для своих полей и отступов",
+	"art is %dx%d but the canvas only has room for %dx%d":   "арт размером %dx%d, а на холсте помещается только %dx%d",
+
+	// config.go
+	"failed to open %s: %v":           "не удалось открыть %s: %v",
+	"%s:%d: expected key = value":     "%s:%d: ожидается ключ = значение",
+	"%s:%d: unknown setting %q":       "%s:%d: неизвестная настройка %q",
+	"failed to read %s: %v":           "не удалось прочитать %s: %v",
+	"%s: invalid value %q for %s: %v": "%s: недопустимое значение %q для %s: %v",
+
+	// exit.go
+	"invalid banner file": "повреждённый файл баннера",
+
+	// fill.go
+	"fill pattern %q must be one or more single-width characters": "шаблон заполнения %q должен состоять из символов одинарной ширины",
+
+	// flags.go
+	"unknown flag %q (use -- before text that starts with '-')": "неизвестный флаг %q (перед текстом, начинающимся с '-', поставьте --)",
+	"flag %s does not take a value (got %q)":                    "флаг %s не принимает значения (получено %q)",
+	"flag %s needs a value":                                     "флагу %s нужно значение",
+	"invalid value %q for %s: %v":                               "недопустимое значение %q для %s: %v",
+	"cannot be combined with --%s":                              "нельзя использовать вместе с --%s",
+	"not a number":                                              "не число",
+	"must be at least %d":                                       "должно быть не меньше %d",
+	"must be one of %s":                                         "должно быть одним из: %s",
+
+	// input.go
+	"failed to read stdin: %v": "не удалось прочитать stdin: %v",
+
+	// main.go
+	"unexpected argument %q":                                           "лишний аргумент %q",
+	"banner given twice: --banner=%s and %q":                           "баннер указан дважды: --banner=%s и %q",
+	"loading banner: %v":                                               "при загрузке баннера: %v",
+	"left, right, center or justify":                                   "left, right, center или justify",
+	"align to N columns instead of the terminal width":                 "выравнивать по N колонкам вместо ширины терминала",
+	"do not wrap text wider than the width":                            "не переносить текст шире заданной ширины",
+	"color the art":                                                    "раскрасить арт",
+	"right-to-left text (same as --direction=rtl)":                     "текст справа налево (то же, что --direction=rtl)",
+	"ltr, rtl or auto":                                                 "ltr, rtl или auto",
+	"fill alignment padding and justify gaps with PATTERN":             "заполнять отступы выравнивания и промежутки justify шаблоном PATTERN",
+	"columns of fill always kept left of the art":                      "колонок заполнения всегда слева от арта",
+	"columns of fill always kept right of the art":                     "колонок заполнения всегда справа от арта",
+	"place the art in a fixed-size box":                                "поместить арт в холст заданного размера",
+	"top, middle or bottom within the canvas":                          "top, middle или bottom внутри холста",
+	"space around the canvas border: N, V,H or T,R,B,L":                "поля вокруг рамки холста: N, V,H или T,R,B,L",
+	"space inside the canvas border: N, V,H or T,R,B,L":                "отступы внутри рамки холста: N, V,H или T,R,B,L",
+	"draw a border around the canvas":                                  "нарисовать рамку вокруг холста",
+	"clip art larger than the canvas, or fail with an error (default)": "обрезать арт больше холста или завершиться с ошибкой (по умолчанию)",
+	"banner to use (default standard)":                                 "баннер (по умолчанию standard)",
+	"read the text from FILE ('-' is stdin); without a STRING\nthe text is read from stdin when it is piped": "читать текст из FILE ('-' — stdin); без STRING текст\nчитается из stdin, если ввод перенаправлен",
+	"missing file name":                       "не указано имя файла",
+	"redraw whenever the terminal is resized": "перерисовывать при изменении размера терминала",
+	"characters missing from the banner: skip (default), error,\nplaceholder or translit (é→e, ß→ss, curly quotes→straight)": "символы, которых нет в баннере: skip (по умолчанию), error,\nplaceholder или translit (é→e, ß→ss, фигурные кавычки→прямые)",
+	"draw CHAR (or 'box') for missing characters (default '?')":                                                              "рисовать CHAR (или 'box') вместо отсутствующих (по умолчанию '?')",
+	"directories searched for banner files first (':'-separated)":                                                            "каталоги, где сначала ищутся файлы баннеров (через ':')",
+	"mark the end of every line with $":                                                                                      "отметить конец каждой строки знаком $",
+	"strip trailing spaces":                                                                                                  "удалить пробелы в конце строк",
+	"type text and see it rendered at once; :help lists the commands":                                                        "вводить текст и сразу видеть результат; :help — список команд",
+	"pick a font, color and alignment in a full-screen preview":                                                              "выбрать шрифт, цвет и выравнивание в полноэкранном просмотре",
+	"print the effective settings and where they come from":                                                                  "вывести действующие настройки и их источники",
+	"print a completion script for bash, zsh or fish":                                                                        "вывести скрипт дополнения для bash, zsh или fish",
+	"print the manual page in roff format":                                                                                   "вывести man-страницу в формате roff",
+	"list the fonts found in the font path and the bundled ones":                                                             "вывести шрифты из font-path и встроенные",
+	"language of help and messages: en or ru (default: from LC_ALL or LANG)":                                                 "язык справки и сообщений: en или ru (по умолчанию из LC_ALL или LANG)",
+	"show this help":                                 "показать эту справку",
+	"failed to read %s: %w":                          "не удалось прочитать %s: %w",
+	"%w %s: %d of %d characters":                     "%w %s: %d символов из %d",
+	"%w %s: character %q has %d lines instead of %d": "%w %s: у символа %q %d строк вместо %d",
+	"Usage:": "Использование:",
+	"Options (in any order, --flag=value or --flag value; -- ends options):": "Флаги (в любом порядке, --флаг=значение или --флаг значение; -- завершает флаги):",
+	"Example:": "Пример:",
+	"Defaults for banner, color, align, width, font-path, unsupported and placeholder are\nread from $XDG_CONFIG_HOME/ascii-art/config, .asciiartrc and ASCII_ART_* variables.": "Значения по умолчанию для banner, color, align, width, font-path, unsupported и placeholder\nберутся из $XDG_CONFIG_HOME/ascii-art/config, .asciiartrc и переменных ASCII_ART_*.",
+
+	// rawmode_other.go
+	"raw terminal mode is not supported on this platform": "на этой платформе сырой режим терминала не поддерживается",
+
+	// repl.go
+	"Interactive mode, :help lists the commands.": "Интерактивный режим, :help — список команд.",
+	"Type a line of text to render it (\\n starts a new line); an empty line\nrenders the text again. Lines starting with ':' are commands:\n  :font NAME        switch the banner (:fonts lists them)\n  :color NAME|off   color the art\n  :align A          left, right, center or justify\n  :width N|auto     align to N columns, or to the terminal\n  :FLAG VALUE       any other flag, e.g. :fill . or :direction rtl\n  :save FILE        write the last render to FILE, without color\n  :save! FILE       the same, replacing FILE if it exists\n  :help             show this help\n  :quit             leave (so does Ctrl-D)": "Введите строку текста, чтобы нарисовать её (\\n начинает новую строку); пустая\nстрока рисует текст заново. Строки, начинающиеся с ':', — команды:\n  :font NAME        сменить баннер (:fonts — список баннеров)\n  :color NAME|off   раскрасить арт\n  :align A          left, right, center или justify\n  :width N|auto     выравнивать по N колонкам или по терминалу\n  :FLAG VALUE       любой другой флаг, например :fill . или :direction rtl\n  :save FILE        записать последний результат в FILE без цвета\n  :save! FILE       то же, заменяя FILE, если он существует\n  :help             показать эту справку\n  :quit             выйти (так же, как Ctrl-D)",
+	"unknown command :%s (see :help)":           "неизвестная команда :%s (см. :help)",
+	":%s needs a value":                         ":%s требует значения",
+	"invalid value %q for :%s: %v":              "недопустимое значение %q для :%s: %v",
+	":save needs a file name":                   ":save требует имени файла",
+	"nothing to save yet, type some text first": "сохранять пока нечего, сначала введите текст",
+	"Saved to %s\n":                             "Сохранено в %s\n",
+
+	// unsupported.go
+	"unsupported characters":                  "неподдерживаемые символы",
+	"must be a single character or %q":        "должен быть одним символом или %q",
+	"%w: placeholder %q is not in the banner": "%w: заменителя %q нет в баннере",
+	"%q (%U) at %d:%d":                        "%q (%U) в позиции %d:%d",
+
+	// write.go
+	"output path %s is a directory":                    "путь вывода %s — каталог",
+	"refusing to overwrite font file %s":               "файл шрифта %s перезаписывать нельзя",
+	"file %s already exists (use :save! to overwrite)": "файл %s уже существует (перезаписать можно с :save!)",
+	"directory %s does not exist":                      "каталог %s не существует",
+	"failed to create temporary file: %v":              "не удалось создать временный файл: %v",
+	"failed to write %s: %v":                           "не удалось записать %s: %v",
+	"failed to sync %s: %v":                            "не удалось сбросить %s на диск: %v",
+	"failed to chmod %s: %v":                           "не удалось изменить права %s: %v",
+	"failed to close %s: %v":                           "не удалось закрыть %s: %v",
+	"failed to rename %s to %s: %v":                    "не удалось переименовать %s в %s: %v",
+}
@@ -0,0 +1,8 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd || dragonfly)
+
+package main
+
+// makeRaw is not supported on this platform.
+func makeRaw(fd uintptr) (func(), error) {
+	return nil, errorf("raw terminal mode is not supported on this platform")
+}
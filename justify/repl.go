@@ -0,0 +1,222 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// replHelp describes the commands of the interactive mode.
+const replHelp = `Type a line of text to render it (\n starts a new line); an empty line
+renders the text again. Lines starting with ':' are commands:
+  :font NAME        switch the banner (:fonts lists them)
+  :color NAME|off   color the art
+  :align A          left, right, center or justify
+  :width N|auto     align to N columns, or to the terminal
+  :FLAG VALUE       any other flag, e.g. :fill . or :direction rtl
+  :save FILE        write the last render to FILE, without color
+  :save! FILE       the same, replacing FILE if it exists
+  :help             show this help
+  :quit             leave (so does Ctrl-D)`
+
+// replExcluded are the flags that mean nothing inside the interactive mode.
+var replExcluded = map[string]bool{
+	"help": true, "input": true, "watch": true, "repl": true, "browse": true,
+	"show-config": true, "completion": true, "man": true, "list-fonts": true,
+}
+
+// session is the state of the interactive mode: the settings, the text and
+// the banners loaded so far, so switching back to a banner is instant.
+type session struct {
+	opts    options
+	text    string
+	banners map[string]map[rune][]string
+	last    []string // rows of the last render, without color
+	out     io.Writer
+}
+
+// runREPL reads lines from in and re-renders the text after every line,
+// until :quit or the end of the input. The prompt is only shown when asked
+// for, so piped scripts get just the art.
+func runREPL(in io.Reader, out, errOut io.Writer, opts options, prompt bool) error {
+	s, err := newSession(opts, out)
+	if err != nil {
+		return err
+	}
+	if prompt {
+		fmt.Fprintln(out, tr("Interactive mode, :help lists the commands."))
+	}
+
+	scanner := bufio.NewScanner(in)
+	for {
+		if prompt {
+			fmt.Fprintf(out, "%s> ", s.opts.banner)
+		}
+		if !scanner.Scan() {
+			break
+		}
+		line := scanner.Text()
+		if cmd, ok := strings.CutPrefix(line, ":"); ok {
+			name, value, _ := strings.Cut(strings.TrimSpace(cmd), " ")
+			if name == "quit" || name == "q" {
+				return nil
+			}
+			redraw, err := s.command(name, strings.TrimSpace(value))
+			if err != nil {
+				fmt.Fprintln(errOut, tr("Error:"), err)
+			}
+			if err != nil || !redraw {
+				continue
+			}
+		} else if line != "" {
+			s.text = expandNewlines(line)
+		}
+		if err := s.render(); err != nil {
+			fmt.Fprintln(errOut, tr("Error:"), err)
+		}
+	}
+	if prompt {
+		fmt.Fprintln(out)
+	}
+	return scanner.Err()
+}
+
+// newSession starts a session with the settings from the command line and
+// loads the banner they name.
+func newSession(opts options, out io.Writer) (*session, error) {
+	s := &session{opts: opts, banners: make(map[string]map[rune][]string), out: out}
+	if s.opts.banner == "" {
+		s.opts.banner = s.opts.defaultBanner
+	}
+	if s.opts.canvas != nil {
+		s.opts.canvas = &s.opts.box // flags given from now on change this copy
+	}
+	if _, err := s.loadBanner(s.opts.banner); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// loadBanner returns the banner called name, reading it only the first time.
+func (s *session) loadBanner(name string) (map[rune][]string, error) {
+	if banner, ok := s.banners[name]; ok {
+		return banner, nil
+	}
+	banner, err := loadBanner(name, s.opts.fontPath)
+	if err != nil {
+		return nil, err
+	}
+	s.banners[name] = banner
+	return banner, nil
+}
+
+// command runs one ':' command and reports whether the text needs to be
+// drawn again. Settings are changed through the same handlers as the flags,
+// so they are validated the same way.
+func (s *session) command(name, value string) (bool, error) {
+	switch name {
+	case "help", "h":
+		fmt.Fprintln(s.out, tr(replHelp))
+		return false, nil
+	case "fonts":
+		for _, font := range discoverFonts(s.opts.fontPath) {
+			fmt.Fprintln(s.out, font.name)
+		}
+		return false, nil
+	case "save", "save!":
+		return false, s.save(value, name == "save!")
+	case "font", "banner":
+		if _, err := s.loadBanner(value); err != nil {
+			return false, err
+		}
+		s.opts.banner = value
+		return true, nil
+	case "color":
+		if value == "off" {
+			s.opts.color = ""
+			return true, nil
+		}
+	case "width":
+		if value == "auto" {
+			s.opts.width = 0
+			return true, nil
+		}
+	}
+
+	spec := findFlag(s.opts.flagSpecs(), name, len(name) > 1)
+	if spec == nil || replExcluded[spec.name] {
+		return false, errorf("unknown command :%s (see :help)", name)
+	}
+	if spec.hasValue && value == "" {
+		return false, errorf(":%s needs a value", name)
+	}
+	if err := spec.set(value); err != nil {
+		return false, errorf("invalid value %q for :%s: %v", value, name, err)
+	}
+	return true, nil
+}
+
+// render draws the current text with the current settings.
+func (s *session) render() error {
+	if s.text == "" {
+		return nil
+	}
+	width := outputWidth(s.opts.width)
+	plain, err := s.art(width, false)
+	if err != nil {
+		return err
+	}
+	s.last = plain
+	rows := plain
+	if s.opts.color != "" {
+		if rows, err = s.art(width, true); err != nil {
+			return err
+		}
+	}
+	for _, row := range rows {
+		fmt.Fprintln(s.out, row)
+	}
+	return nil
+}
+
+// art renders the current text to the given width, in color if asked to.
+func (s *session) art(width int, colored bool) ([]string, error) {
+	banner, err := s.loadBanner(s.opts.banner)
+	if err != nil {
+		return nil, err
+	}
+	text, err := applyCharPolicy(s.text, banner, s.opts.chars)
+	if err != nil {
+		return nil, err
+	}
+	if colored && s.opts.color != "" {
+		banner = colorBanner(banner, colorCodes[s.opts.color])
+	}
+	lay := s.opts.layout()
+	lay.width = width
+	return renderArt(text, banner, lay, s.opts.canvas, s.opts.ends)
+}
+
+// save writes the last render to a file. An existing file is only replaced
+// with force, and the banner files are never.
+func (s *session) save(path string, force bool) error {
+	if path == "" {
+		return errorf(":save needs a file name")
+	}
+	if s.last == nil {
+		return errorf("nothing to save yet, type some text first")
+	}
+	var fonts []string
+	for _, font := range discoverFonts(s.opts.fontPath) {
+		fonts = append(fonts, font.path)
+	}
+	for name := range s.banners {
+		fonts = append(fonts, bannerFile(name, s.opts.fontPath))
+	}
+	if err := writeFile(path, []byte(strings.Join(s.last, "\n")+"\n"), force, fonts); err != nil {
+		return err
+	}
+	fmt.Fprintf(s.out, tr("Saved to %s\n"), path)
+	return nil
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+)
+
+// outputWidth returns the width to align to: an explicit --width override,
+// then the size of the terminal on stdout, then $COLUMNS, then defaultWidth.
+func outputWidth(override int) int {
+	if override > 0 {
+		return override
+	}
+	if width, ok := terminalWidth(os.Stdout.Fd()); ok {
+		return width
+	}
+	if columns, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && columns > 0 {
+		return columns
+	}
+	return defaultWidth
+}
+
+// isTerminal reports whether stdout is attached to a terminal.
+func isTerminal() bool {
+	_, ok := terminalWidth(os.Stdout.Fd())
+	return ok
+}
+
+// watchResize re-runs render every time the terminal is resized, until
+// the program is interrupted.
+func watchResize(render func()) {
+	resize := make(chan os.Signal, 1)
+	notifyResize(resize)
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM)
+
+	for {
+		clearScreen()
+		render()
+		select {
+		case <-resize:
+		case <-interrupt:
+			return
+		}
+	}
+}
+
+// clearScreen moves the cursor home and clears the terminal.
+func clearScreen() {
+	os.Stdout.WriteString("\033[H\033[2J")
+}
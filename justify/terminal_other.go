@@ -0,0 +1,18 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd || dragonfly)
+
+package main
+
+import "os"
+
+// terminalWidth is not supported on this platform; callers fall back to $COLUMNS.
+func terminalWidth(fd uintptr) (int, bool) {
+	return 0, false
+}
+
+// terminalSize is not supported on this platform.
+func terminalSize(fd uintptr) (int, int, bool) {
+	return 0, 0, false
+}
+
+// notifyResize is a no-op on platforms without SIGWINCH.
+func notifyResize(ch chan<- os.Signal) {}
@@ -0,0 +1,39 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+
+package main
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+	"unsafe"
+)
+
+// winsize mirrors struct winsize filled in by the TIOCGWINSZ ioctl.
+type winsize struct {
+	rows    uint16
+	cols    uint16
+	xpixels uint16
+	ypixels uint16
+}
+
+// terminalWidth queries the column count of the terminal behind fd.
+func terminalWidth(fd uintptr) (int, bool) {
+	cols, _, ok := terminalSize(fd)
+	return cols, ok
+}
+
+// terminalSize queries the columns and rows of the terminal behind fd.
+func terminalSize(fd uintptr) (int, int, bool) {
+	var ws winsize
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, uintptr(syscall.TIOCGWINSZ), uintptr(unsafe.Pointer(&ws)))
+	if errno != 0 || ws.cols == 0 {
+		return 0, 0, false
+	}
+	return int(ws.cols), int(ws.rows), true
+}
+
+// notifyResize delivers SIGWINCH to ch whenever the terminal is resized.
+func notifyResize(ch chan<- os.Signal) {
+	signal.Notify(ch, syscall.SIGWINCH)
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Policies for characters the banner has no glyph for (--unsupported).
+const (
+	policySkip        = "skip"        // drop the character (default)
+	policyError       = "error"       // fail, listing the characters
+	policyPlaceholder = "placeholder" // draw a placeholder glyph instead (--placeholder)
+	policyTranslit    = "translit"    // replace with look-alike ASCII: é→e, ß→ss, “→"
+)
+
+// boxPlaceholder is the --placeholder value that draws an empty box.
+const boxPlaceholder = "box"
+
+// boxRune is the rune the box glyph is added to the banner under.
+const boxRune = '\uFFFD'
+
+// errUnsupported wraps errors about characters missing from the banner.
+var errUnsupported = message("unsupported characters")
+
+// charPolicy says what to do with characters missing from the banner.
+type charPolicy struct {
+	mode        string
+	placeholder string // a character of the banner or "box"
+}
+
+// validatePlaceholder checks a --placeholder value: one character or "box".
+func validatePlaceholder(value string) error {
+	if value != boxPlaceholder && utf8.RuneCountInString(value) != 1 {
+		return errorf("must be a single character or %q", boxPlaceholder)
+	}
+	return nil
+}
+
+// translitLower transliterates lowercase letters; uppercase ones are derived
+// from their lowercase form (Ж→Zh, É→E).
+var translitLower = map[rune]string{
+	'à': "a", 'á': "a", 'â': "a", 'ã': "a", 'ä': "a", 'å': "a", 'ā': "a", 'ă': "a", 'ą': "a",
+	'æ': "ae", 'ç': "c", 'ć': "c", 'č': "c", 'ď': "d", 'đ': "d", 'ð': "d",
+	'è': "e", 'é': "e", 'ê': "e", 'ë': "e", 'ē': "e", 'ę': "e", 'ě': "e", 'ğ': "g",
+	'ì': "i", 'í': "i", 'î': "i", 'ï': "i", 'ī': "i", 'ı': "i", 'ł': "l", 'ľ': "l",
+	'ñ': "n", 'ń': "n", 'ň': "n", 'ò': "o", 'ó': "o", 'ô': "o", 'õ': "o", 'ö': "o", 'ø': "o", 'ő': "o",
+	'œ': "oe", 'ŕ': "r", 'ř': "r", 'ß': "ss", 'ś': "s", 'š': "s", 'ş': "s", 'ť': "t", 'ţ': "t", 'þ': "th",
+	'ù': "u", 'ú': "u", 'û': "u", 'ü': "u", 'ū': "u", 'ů': "u", 'ű': "u",
+	'ý': "y", 'ÿ': "y", 'ź': "z", 'ż': "z", 'ž': "z",
+	// Russian alphabet, simplified transliteration
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "e", 'ж': "zh",
+	'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o",
+	'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "kh", 'ц': "ts",
+	'ч': "ch", 'ш': "sh", 'щ': "shch", 'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu", 'я': "ya",
+}
+
+// translitSymbols transliterates quotes, dashes and other symbols.
+var translitSymbols = map[rune]string{
+	'‘': "'", '’': "'", '‚': "'", '‛': "'", '′': "'", '‹': "'", '›': "'",
+	'“': "\"", '”': "\"", '„': "\"", '‟': "\"", '″': "\"", '«': "\"", '»': "\"",
+	'‐': "-", '‑': "-", '‒': "-", '–': "-", '—': "-", '―': "-", '−': "-",
+	'…': "...", '•': "*", '·': ".", '×': "x", '÷': "/", '\u00A0': " ", '\t': " ",
+	'©': "(c)", '®': "(R)", '™': "TM", '€': "EUR", '№': "No",
+}
+
+// transliterate returns an ASCII replacement for r.
+func transliterate(r rune) (string, bool) {
+	if s, ok := translitSymbols[r]; ok {
+		return s, true
+	}
+	if s, ok := translitLower[r]; ok {
+		return s, true
+	}
+	if lower := unicode.ToLower(r); lower != r {
+		if s, ok := translitLower[lower]; ok {
+			if s == "" {
+				return s, true
+			}
+			return strings.ToUpper(s[:1]) + s[1:], true
+		}
+	}
+	return "", false
+}
+
+// applyCharPolicy prepares text for rendering: characters missing from the
+// banner are dropped, replaced by a placeholder or transliterated. With the
+// error policy (and for characters that cannot be transliterated) it returns
+// an error listing the characters and their positions as line:column.
+func applyCharPolicy(input string, banner map[rune][]string, policy charPolicy) (string, error) {
+	placeholder := ""
+	if policy.mode == policyPlaceholder {
+		if policy.placeholder == boxPlaceholder {
+			addBoxGlyph(banner)
+			placeholder = string(boxRune)
+		} else if _, ok := banner[[]rune(policy.placeholder)[0]]; ok {
+			placeholder = policy.placeholder
+		} else {
+			return "", errorf("%w: placeholder %q is not in the banner", errUnsupported, policy.placeholder)
+		}
+	}
+
+	var b strings.Builder
+	var bad []string
+	line, col := 1, 0
+	for _, r := range input {
+		// Line breaks: a \n in an argument is already a real newline
+		if r == '\n' {
+			b.WriteRune(r)
+			line, col = line+1, 0
+			continue
+		}
+		col++
+		if _, ok := banner[r]; ok {
+			b.WriteRune(r)
+			continue
+		}
+		switch policy.mode {
+		case policySkip:
+		case policyPlaceholder:
+			b.WriteString(placeholder)
+		case policyTranslit:
+			if s, ok := transliterate(r); ok && supports(banner, s) {
+				b.WriteString(s)
+				continue
+			}
+			bad = append(bad, fmt.Sprintf(tr("%q (%U) at %d:%d"), r, r, line, col))
+		default:
+			bad = append(bad, fmt.Sprintf(tr("%q (%U) at %d:%d"), r, r, line, col))
+		}
+	}
+	if len(bad) > 0 {
+		return "", errorf("%w: %s", errUnsupported, strings.Join(bad, ", "))
+	}
+	return b.String(), nil
+}
+
+// supports reports whether the banner has a glyph for every character of s.
+func supports(banner map[rune][]string, s string) bool {
+	for _, r := range s {
+		if _, ok := banner[r]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// addBoxGlyph adds an empty box glyph as wide as '?' to the banner.
+func addBoxGlyph(banner map[rune][]string) {
+	width := 4
+	for _, line := range banner['?'] {
+		width = max(width, displayWidth(line))
+	}
+	inner := width - 3 // the two sides plus the space after the glyph
+	glyph := make([]string, bannerHeight)
+	for i := range glyph {
+		switch i {
+		case 0, len(glyph) - 1:
+			glyph[i] = "+" + strings.Repeat("-", inner) + "+ "
+		default:
+			glyph[i] = "|" + strings.Repeat(" ", inner) + "| "
+		}
+	}
+	banner[boxRune] = glyph
+}
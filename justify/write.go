@@ -0,0 +1,77 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// writeFile writes data to path the way the output program writes its
+// files: font files are never replaced, an existing file only with force,
+// and the data goes to a temporary file in the same directory that is then
+// renamed over path, so a failed write leaves the old file as it was.
+func writeFile(path string, data []byte, force bool, fonts []string) error {
+	if info, err := os.Stat(path); err == nil {
+		switch {
+		case info.IsDir():
+			return errorf("output path %s is a directory", path)
+		case isProtected(info, fonts):
+			return errorf("refusing to overwrite font file %s", path)
+		case !force:
+			return errorf("file %s already exists (use :save! to overwrite)", path)
+		}
+	}
+	if dir := filepath.Dir(path); !isDir(dir) {
+		return errorf("directory %s does not exist", dir)
+	}
+	return writeAtomic(path, data)
+}
+
+// isProtected reports whether target is one of the font files.
+func isProtected(target os.FileInfo, fonts []string) bool {
+	for _, font := range fonts {
+		if info, err := os.Stat(font); err == nil && os.SameFile(target, info) {
+			return true
+		}
+	}
+	return false
+}
+
+// isDir reports whether path is an existing directory.
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+// writeAtomic writes data to a temporary file and renames it to path.
+func writeAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return errorf("failed to create temporary file: %v", err)
+	}
+	tmpName := tmp.Name()
+	// Remove the temporary file if anything goes wrong
+	cleanup := func(err error) error {
+		tmp.Close()
+		os.Remove(tmpName)
+		return err
+	}
+
+	if _, err := tmp.Write(data); err != nil {
+		return cleanup(errorf("failed to write %s: %v", tmpName, err))
+	}
+	if err := tmp.Sync(); err != nil {
+		return cleanup(errorf("failed to sync %s: %v", tmpName, err))
+	}
+	if err := tmp.Chmod(0644); err != nil {
+		return cleanup(errorf("failed to chmod %s: %v", tmpName, err))
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpName)
+		return errorf("failed to close %s: %v", tmpName, err)
+	}
+	if err := os.Rename(tmpName, path); err != nil {
+		os.Remove(tmpName)
+		return errorf("failed to rename %s to %s: %v", tmpName, path, err)
+	}
+	return nil
+}
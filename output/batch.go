@@ -0,0 +1,216 @@
+package main
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// batchIndexName — имя файла-индекса внутри архива
+const batchIndexName = "index.tsv"
+
+// batchEntry описывает один баннер пакетного режима
+type batchEntry struct {
+	text   string // текст баннера; \n из файла уже заменён переводом строки
+	banner string // тип баннера
+	path   string // путь к результату (или имя внутри архива)
+}
+
+// readBatch читает файл пакетного режима.
+// Каждая непустая строка имеет вид: ТЕКСТ[<TAB>БАННЕР[<TAB>ПУТЬ]];
+// строки, начинающиеся с #, считаются комментариями.
+func readBatch(filename, defaultBanner string) ([]batchEntry, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, errorf("failed to open %s: %v", filename, err)
+	}
+	defer file.Close()
+
+	var entries []batchEntry
+	scanner := bufio.NewScanner(file)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Split(line, "\t")
+		if len(fields) > 3 {
+			return nil, withExitCode(exitUsage, errorf("%s:%d: too many fields", filename, lineNum))
+		}
+		// Строка файла не может содержать перевод строки, поэтому \n в TEXT,
+		// как и в аргументе, начинает новую строку
+		entry := batchEntry{text: expandNewlines(fields[0]), banner: defaultBanner}
+		if len(fields) > 1 && fields[1] != "" {
+			entry.banner = fields[1]
+		}
+		if len(fields) > 2 && fields[2] != "" {
+			entry.path = fields[2]
+		} else {
+			entry.path = fmt.Sprintf("banner-%03d.txt", len(entries)+1)
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errorf("failed to read %s: %v", filename, err)
+	}
+	return entries, nil
+}
+
+// runBatch рендерит все баннеры из файла пакетного режима и записывает их
+// либо в отдельные файлы, либо в один архив (--archive)
+func runBatch(opts options, defaultBanner string) error {
+	entries, err := readBatch(opts.batchFile, defaultBanner)
+	if err != nil {
+		return err
+	}
+
+	// Шрифты загружаем один раз на тип баннера
+	fonts := make(map[string]*ASCIIArt)
+	files := make([][]byte, len(entries))
+	for i, entry := range entries {
+		ascii, ok := fonts[entry.banner]
+		if !ok {
+			ascii = NewASCIIArt()
+			ascii.width = opts.wrapWidth()
+			fontFile := resolveFont(entry.banner+".txt", opts.fontPath)
+			if err := ascii.LoadFont(fontFile); err != nil {
+				return withExitCode(fontExitCode(err), err)
+			}
+			fonts[entry.banner] = ascii
+			opts.write.protected = append(opts.write.protected, fontFile)
+		}
+		dest, err := parseDestination(entry.path)
+		if err != nil {
+			return withExitCode(exitUsage, err)
+		}
+		// Внутри архива имя не должно содержать префикс формата
+		entries[i].path = dest.path
+		text, err := ascii.applyCharPolicy(entry.text, opts.chars)
+		if err != nil {
+			return withExitCode(exitUnsupported, errorf("%s: %w", entry.path, err))
+		}
+		output := applyLineEnds(ascii.RenderText(text), opts.ends)
+		if files[i], err = renderDestination(output, dest, opts); err != nil {
+			return err
+		}
+	}
+
+	if opts.archive == "" {
+		for i, entry := range entries {
+			if err := writeOutput(entry.path, files[i], opts.write); err != nil {
+				if errors.Is(err, errSkipped) {
+					warn("File %s already exists, skipped", entry.path)
+					continue
+				}
+				return err
+			}
+		}
+		return nil
+	}
+
+	data, err := buildArchive(opts.archive, entries, files)
+	if err != nil {
+		return err
+	}
+	return writeOutput(opts.archive, data, opts.write)
+}
+
+// batchIndex строит файл-индекс: текст, шрифт и путь для каждого баннера
+func batchIndex(entries []batchEntry) []byte {
+	var b strings.Builder
+	b.WriteString("text\tfont\tpath\n")
+	for _, entry := range entries {
+		// Переводы строк записываются как \n, как в файле пакетного режима
+		text := strings.ReplaceAll(entry.text, "\n", `\n`)
+		fmt.Fprintf(&b, "%s\t%s\t%s\n", text, entry.banner, entry.path)
+	}
+	return []byte(b.String())
+}
+
+// isArchivePath проверяет, поддерживается ли формат архива по имени файла
+func isArchivePath(path string) bool {
+	name := strings.ToLower(path)
+	for _, ext := range []string{".tar", ".tar.gz", ".tgz", ".zip"} {
+		if strings.HasSuffix(name, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// buildArchive упаковывает баннеры и индекс в tar (.tar, .tar.gz, .tgz) или zip (.zip)
+func buildArchive(path string, entries []batchEntry, files [][]byte) ([]byte, error) {
+	names := []string{batchIndexName}
+	contents := [][]byte{batchIndex(entries)}
+	seen := map[string]bool{batchIndexName: true}
+	for i, entry := range entries {
+		name := filepath.ToSlash(filepath.Clean(entry.path))
+		if filepath.IsAbs(entry.path) || name == ".." || strings.HasPrefix(name, "../") {
+			return nil, withExitCode(exitUsage, errorf("archive entry %s must be a relative path inside the archive", entry.path))
+		}
+		if seen[name] {
+			return nil, withExitCode(exitUsage, errorf("duplicate archive entry %s", name))
+		}
+		seen[name] = true
+		names = append(names, name)
+		contents = append(contents, files[i])
+	}
+
+	var buf bytes.Buffer
+	now := time.Now()
+	lower := strings.ToLower(path)
+	if strings.HasSuffix(lower, ".zip") {
+		zw := zip.NewWriter(&buf)
+		for i, name := range names {
+			w, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: now})
+			if err != nil {
+				return nil, errorf("failed to add %s to archive: %v", name, err)
+			}
+			if _, err := w.Write(contents[i]); err != nil {
+				return nil, errorf("failed to add %s to archive: %v", name, err)
+			}
+		}
+		if err := zw.Close(); err != nil {
+			return nil, errorf("failed to finish archive: %v", err)
+		}
+		return buf.Bytes(), nil
+	}
+
+	// .tar.gz и .tgz — тот же tar, пропущенный через gzip
+	var zw *gzip.Writer
+	var out io.Writer = &buf
+	if strings.HasSuffix(lower, ".gz") || strings.HasSuffix(lower, ".tgz") {
+		zw = gzip.NewWriter(&buf)
+		out = zw
+	}
+	tw := tar.NewWriter(out)
+	for i, name := range names {
+		hdr := &tar.Header{Name: name, Mode: 0644, Size: int64(len(contents[i])), ModTime: now}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return nil, errorf("failed to add %s to archive: %v", name, err)
+		}
+		if _, err := tw.Write(contents[i]); err != nil {
+			return nil, errorf("failed to add %s to archive: %v", name, err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, errorf("failed to finish archive: %v", err)
+	}
+	if zw != nil {
+		if err := zw.Close(); err != nil {
+			return nil, errorf("failed to finish archive: %v", err)
+		}
+	}
+	return buf.Bytes(), nil
+}
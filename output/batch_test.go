@@ -0,0 +1,137 @@
+package main
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// batchSample — файл пакетного режима: с комментарием, пустой строкой,
+// \n в тексте и строкой без баннера и пути
+const batchSample = "# banners\nhi\tstandard\thi.txt\n\na\\nb\tshadow\tsub/ab.txt\nx\n"
+
+func TestBatchArchive(t *testing.T) {
+	wantIndex := "text\tfont\tpath\n" +
+		"hi\tstandard\thi.txt\n" +
+		"a\\nb\tshadow\tsub/ab.txt\n" +
+		"x\tstandard\tbanner-003.txt\n"
+	wantFiles := map[string]string{
+		batchIndexName:   wantIndex,
+		"hi.txt":         renderWith(t, "standard.txt", "hi"),
+		"sub/ab.txt":     renderWith(t, "shadow.txt", "a\nb"),
+		"banner-003.txt": renderWith(t, "standard.txt", "x"),
+	}
+	for _, name := range []string{"out.tar", "out.tar.gz", "out.tgz", "out.zip"} {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			batch := filepath.Join(dir, "banners.tsv")
+			mustWrite(t, batch, batchSample)
+			archive := filepath.Join(dir, name)
+			opts := options{batchFile: batch, archive: archive, chars: charPolicy{mode: policySkip}}
+			if err := runBatch(opts, "standard"); err != nil {
+				t.Fatal(err)
+			}
+			got := readArchive(t, archive)
+			if len(got) != len(wantFiles) {
+				t.Errorf("archive holds %d files, want %d", len(got), len(wantFiles))
+			}
+			for file, want := range wantFiles {
+				if got[file] != want {
+					t.Errorf("%s = %q, want %q", file, got[file], want)
+				}
+			}
+		})
+	}
+}
+
+func TestBuildArchiveRejects(t *testing.T) {
+	tests := []struct {
+		name  string
+		paths []string
+	}{
+		{"parent directory", []string{"../x.txt"}},
+		{"absolute path", []string{"/tmp/x.txt"}},
+		{"duplicate", []string{"a.txt", "./a.txt"}},
+		{"index name", []string{batchIndexName}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries := make([]batchEntry, len(tt.paths))
+			files := make([][]byte, len(tt.paths))
+			for i, path := range tt.paths {
+				entries[i] = batchEntry{text: "x", banner: "standard", path: path}
+			}
+			if _, err := buildArchive("out.tar", entries, files); err == nil {
+				t.Errorf("buildArchive(%q) succeeded", tt.paths)
+			}
+		})
+	}
+}
+
+// renderWith рисует текст шрифтом из файла, как его рисует пакетный режим
+func renderWith(t *testing.T, font, text string) string {
+	t.Helper()
+	ascii := NewASCIIArt()
+	if err := ascii.LoadFont(font); err != nil {
+		t.Fatal(err)
+	}
+	return ascii.RenderText(text)
+}
+
+// readArchive возвращает содержимое файлов архива tar, tar.gz или zip по именам
+func readArchive(t *testing.T, path string) map[string]string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := make(map[string]string)
+	if strings.HasSuffix(path, ".zip") {
+		zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, f := range zr.File {
+			r, err := f.Open()
+			if err != nil {
+				t.Fatal(err)
+			}
+			content, err := io.ReadAll(r)
+			r.Close()
+			if err != nil {
+				t.Fatal(err)
+			}
+			files[f.Name] = string(content)
+		}
+		return files
+	}
+
+	var r io.Reader = bytes.NewReader(data)
+	if strings.HasSuffix(path, ".gz") || strings.HasSuffix(path, ".tgz") {
+		if r, err = gzip.NewReader(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tarReader := tar.NewReader(r)
+	for {
+		hdr, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, err := io.ReadAll(tarReader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[hdr.Name] = string(content)
+	}
+	return files
+}
@@ -0,0 +1,297 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Описание программы для man-страницы
+const (
+	manSummary     = "write an ASCII-art banner to files, archives or stdout"
+	manDescription = "Renders STRING (or the text read from a file or stdin) with one of the banner fonts " +
+		"and writes it to every --output destination as plain text, HTML or ANSI color, " +
+		"optionally wrapped in a Markdown block or source comments. --batch renders many banners at once."
+)
+
+// completionShells — оболочки, для которых печатается скрипт дополнения
+var completionShells = []string{"bash", "zsh", "fish"}
+
+// programName возвращает имя, под которым запущена программа;
+// под ним регистрируются скрипты дополнения
+func programName() string {
+	return strings.TrimSuffix(filepath.Base(os.Args[0]), ".exe")
+}
+
+// shellIdent превращает имя программы в допустимое имя функции оболочки
+func shellIdent(prog string) string {
+	return regexp.MustCompile(`[^A-Za-z0-9_]`).ReplaceAllString(prog, "_")
+}
+
+// oneLine склеивает многострочное описание флага в одну строку
+func oneLine(usage string) string {
+	return strings.ReplaceAll(usage, "\n", " ")
+}
+
+// writeCompletion печатает скрипт дополнения для оболочки shell
+func writeCompletion(w io.Writer, shell, prog string, specs []flagSpec) {
+	switch shell {
+	case "bash":
+		writeBashCompletion(w, prog, specs)
+	case "zsh":
+		writeZshCompletion(w, prog, specs)
+	case "fish":
+		writeFishCompletion(w, prog, specs)
+	}
+}
+
+// flagNames возвращает длинное и короткое имя флага: "--width", "-w"
+func flagNames(spec flagSpec) []string {
+	names := []string{"--" + spec.name}
+	if spec.short != "" {
+		names = append(names, "-"+spec.short)
+	}
+	return names
+}
+
+// Скрипты дополнения узнают шрифты, запуская саму программу с флагом --list-fonts,
+// поэтому шрифты из font-path и настроек всегда актуальны.
+
+func writeBashCompletion(w io.Writer, prog string, specs []flagSpec) {
+	fn := "_" + shellIdent(prog)
+	var all, valued []string
+	for _, spec := range specs {
+		all = append(all, flagNames(spec)...)
+		if spec.hasValue {
+			valued = append(valued, flagNames(spec)...)
+		}
+	}
+
+	fmt.Fprintf(w, "# bash completion for %s\n", prog)
+	fmt.Fprintf(w, "# load it with: source <(%s --completion=bash)\n", prog)
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, `# Candidates go through mapfile so that values such as "/*" are not globbed`)
+	fmt.Fprintf(w, "%s_reply() {\n", fn)
+	fmt.Fprintln(w, `    mapfile -t COMPREPLY < <(compgen "$@" -- "$cur")`)
+	fmt.Fprintln(w, `}`)
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "%s() {\n", fn)
+	fmt.Fprintln(w, `    local cur=${COMP_WORDS[COMP_CWORD]} prev=${COMP_WORDS[COMP_CWORD-1]}`)
+	fmt.Fprintln(w, `    # "--flag=value" is split into "--flag", "=" and "value"`)
+	fmt.Fprintln(w, `    if [[ $cur == = ]]; then`)
+	fmt.Fprintln(w, `        cur=`)
+	fmt.Fprintln(w, `    elif [[ $prev == = ]]; then`)
+	fmt.Fprintln(w, `        prev=${COMP_WORDS[COMP_CWORD-2]}`)
+	fmt.Fprintln(w, `    fi`)
+	fmt.Fprintln(w, `    case $prev in`)
+	for _, spec := range specs {
+		if !spec.hasValue {
+			continue
+		}
+		reply := ""
+		switch {
+		case len(spec.values) > 0:
+			reply = fmt.Sprintf(`%s_reply -W "%s"`, fn, strings.Join(spec.values, " "))
+		case spec.complete == completeFont:
+			reply = fmt.Sprintf(`%s_reply -W "$("$1" --list-fonts 2>/dev/null)"`, fn)
+		case spec.complete == completeFile:
+			reply = fn + "_reply -f"
+		case spec.complete == completeDir:
+			reply = fn + "_reply -d"
+		}
+		if reply == "" {
+			fmt.Fprintf(w, "    %s) return ;;\n", strings.Join(flagNames(spec), "|"))
+		} else {
+			fmt.Fprintf(w, "    %s) %s; return ;;\n", strings.Join(flagNames(spec), "|"), reply)
+		}
+	}
+	fmt.Fprintln(w, `    esac`)
+	fmt.Fprintln(w, `    if [[ $cur == -?* ]]; then`)
+	fmt.Fprintf(w, "        %s_reply -W \"%s\"\n", fn, strings.Join(all, " "))
+	fmt.Fprintln(w, `        return`)
+	fmt.Fprintln(w, `    fi`)
+	fmt.Fprintln(w, `    # Collect the positional arguments before the cursor, skipping flag values`)
+	fmt.Fprintln(w, `    local i word skip= args=()`)
+	fmt.Fprintln(w, `    for ((i = 1; i < COMP_CWORD; i++)); do`)
+	fmt.Fprintln(w, `        word=${COMP_WORDS[i]}`)
+	fmt.Fprintln(w, `        if [[ $word == = ]]; then skip=1; continue; fi`)
+	fmt.Fprintln(w, `        if [[ -n $skip ]]; then skip=; continue; fi`)
+	fmt.Fprintln(w, `        case $word in`)
+	fmt.Fprintf(w, "        %s) skip=1 ;;\n", strings.Join(valued, "|"))
+	fmt.Fprintln(w, `        -?*) ;;`)
+	fmt.Fprintln(w, `        *) args+=("$word") ;;`)
+	fmt.Fprintln(w, `        esac`)
+	fmt.Fprintln(w, `    done`)
+	fmt.Fprintln(w, `    # The first argument is the text, banner names come after it`)
+	fmt.Fprintln(w, `    if ((${#args[@]} > 0)); then`)
+	fmt.Fprintf(w, "        %s_reply -W \"$(\"$1\" --list-fonts 2>/dev/null)\"\n", fn)
+	fmt.Fprintln(w, `    fi`)
+	fmt.Fprintln(w, `}`)
+	fmt.Fprintf(w, "complete -F %s %s\n", fn, prog)
+}
+
+// zshQuote экранирует текст для описания в спецификации _arguments
+func zshQuote(s string) string {
+	return strings.NewReplacer(`'`, `'\''`, `[`, `\[`, `]`, `\]`, `:`, `\:`).Replace(s)
+}
+
+func writeZshCompletion(w io.Writer, prog string, specs []flagSpec) {
+	fn := "_" + shellIdent(prog)
+	fmt.Fprintf(w, "#compdef %s\n", prog)
+	fmt.Fprintf(w, "# zsh completion for %s\n", prog)
+	fmt.Fprintf(w, "# load it with: source <(%s --completion=zsh), or save it as %s in $fpath\n\n", prog, fn)
+	fmt.Fprintf(w, "%s_fonts() {\n", fn)
+	fmt.Fprintln(w, `    local -a fonts`)
+	fmt.Fprintln(w, `    fonts=(${(f)"$(${words[1]} --list-fonts 2>/dev/null)"})`)
+	fmt.Fprintln(w, `    _describe -t fonts font fonts`)
+	fmt.Fprintln(w, `}`)
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "%s() {\n", fn)
+	fmt.Fprintln(w, `    _arguments -s -S \`)
+	for _, spec := range specs {
+		help := "[" + zshQuote(oneLine(spec.usage)) + "]"
+		action := ""
+		if spec.hasValue {
+			switch {
+			case len(spec.values) > 0:
+				action = ":" + spec.arg + ":(" + strings.Join(spec.values, " ") + ")"
+			case spec.complete == completeFont:
+				action = ":" + spec.arg + ":" + fn + "_fonts"
+			case spec.complete == completeFile:
+				action = ":" + spec.arg + ":_files"
+			case spec.complete == completeDir:
+				action = ":" + spec.arg + ":_files -/"
+			default:
+				action = ":" + spec.arg + ": "
+			}
+		}
+		long, short := "--"+spec.name, "-"+spec.short
+		if spec.hasValue {
+			long, short = long+"=", short+"+"
+		}
+		if spec.short == "" {
+			fmt.Fprintf(w, "        '%s%s%s' \\\n", long, help, action)
+		} else {
+			fmt.Fprintf(w, "        '(-%s --%s)'{%s,%s}'%s%s' \\\n", spec.short, spec.name, short, long, help, action)
+		}
+	}
+	fmt.Fprintln(w, `        '1:text: ' \`)
+	fmt.Fprintf(w, "        '*:banner:%s_fonts'\n", fn)
+	fmt.Fprintln(w, `}`)
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "if [[ $funcstack[1] == %s ]]; then\n", fn)
+	fmt.Fprintf(w, "    %s \"$@\"\n", fn)
+	fmt.Fprintln(w, `else`)
+	fmt.Fprintf(w, "    compdef %s %s\n", fn, prog)
+	fmt.Fprintln(w, `fi`)
+}
+
+// fishQuote заключает текст в одинарные кавычки fish
+func fishQuote(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s) + "'"
+}
+
+func writeFishCompletion(w io.Writer, prog string, specs []flagSpec) {
+	fn := "__" + shellIdent(prog) + "_fonts"
+	fmt.Fprintf(w, "# fish completion for %s\n", prog)
+	fmt.Fprintf(w, "# load it with: %s --completion=fish | source\n", prog)
+	fmt.Fprintf(w, "function %s\n", fn)
+	fmt.Fprintln(w, `    set -l cmd (commandline -opc)`)
+	fmt.Fprintln(w, `    $cmd[1] --list-fonts 2>/dev/null`)
+	fmt.Fprintln(w, `end`)
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "complete -c %s -f\n", prog)
+	// Первый аргумент — текст, после него дополняются имена шрифтов
+	fmt.Fprintf(w, "complete -c %s -n 'not __fish_use_subcommand' -a '(%s)'\n", prog, fn)
+	for _, spec := range specs {
+		line := "complete -c " + prog
+		if spec.short != "" {
+			line += " -s " + spec.short
+		}
+		line += " -l " + spec.name
+		if spec.hasValue {
+			switch {
+			case len(spec.values) > 0:
+				line += " -x -a " + fishQuote(strings.Join(spec.values, " "))
+			case spec.complete == completeFont:
+				line += " -x -a '(" + fn + ")'"
+			case spec.complete == completeFile:
+				line += " -r -F"
+			case spec.complete == completeDir:
+				line += " -x -a '(__fish_complete_directories)'"
+			default:
+				line += " -x"
+			}
+		}
+		fmt.Fprintln(w, line+" -d "+fishQuote(oneLine(spec.usage)))
+	}
+}
+
+// roffEscape экранирует текст для roff: обратную косую черту, дефисы
+// и точку или апостроф в начале строки, которые roff принял бы за команду
+func roffEscape(s string) string {
+	s = strings.NewReplacer(`\`, `\e`, "-", `\-`).Replace(s)
+	if strings.HasPrefix(s, ".") || strings.HasPrefix(s, "'") {
+		s = `\&` + s
+	}
+	return s
+}
+
+// writeManPage печатает man-страницу, собранную из описаний флагов
+func writeManPage(w io.Writer, prog string, specs []flagSpec) {
+	name := roffEscape(prog)
+	fmt.Fprintf(w, ".TH %s 1 \"\" \"ascii-art\" \"User Commands\"\n", strings.ToUpper(name))
+	fmt.Fprintln(w, ".SH NAME")
+	fmt.Fprintf(w, "%s \\- %s\n", name, roffEscape(manSummary))
+	fmt.Fprintln(w, ".SH SYNOPSIS")
+	for i, form := range usageSynopses {
+		if i > 0 {
+			fmt.Fprintln(w, ".br")
+		}
+		fmt.Fprintf(w, ".B %s\n%s\n", name, roffEscape(form))
+	}
+	fmt.Fprintln(w, ".SH DESCRIPTION")
+	fmt.Fprintln(w, roffEscape(manDescription))
+	fmt.Fprintln(w, ".PP")
+	fmt.Fprintln(w, "Options may appear anywhere on the command line, as \\fB\\-\\-flag\\fR=\\fIvalue\\fR or")
+	fmt.Fprintln(w, "\\fB\\-\\-flag\\fR \\fIvalue\\fR; \\fB\\-\\-\\fR ends the options.")
+
+	fmt.Fprintln(w, ".SH OPTIONS")
+	for _, spec := range specs {
+		fmt.Fprintln(w, ".TP")
+		label := `\fB\-\-` + roffEscape(spec.name) + `\fR`
+		if spec.short != "" {
+			label = `\fB\-` + roffEscape(spec.short) + `\fR, ` + label
+		}
+		if spec.hasValue {
+			label += `=\fI` + roffEscape(spec.arg) + `\fR`
+		}
+		fmt.Fprintln(w, label)
+		fmt.Fprintln(w, roffEscape(oneLine(spec.usage)))
+	}
+
+	fmt.Fprintln(w, ".SH ENVIRONMENT")
+	for _, spec := range specs {
+		if isConfigKey(spec.name) {
+			fmt.Fprintf(w, ".TP\n.B %s\nDefault for \\fB\\-\\-%s\\fR.\n", roffEscape(envName(spec.name)), roffEscape(spec.name))
+		}
+	}
+
+	fmt.Fprintln(w, ".SH FILES")
+	fmt.Fprintln(w, ".TP")
+	fmt.Fprintln(w, `.I $XDG_CONFIG_HOME/ascii\-art/config`)
+	fmt.Fprintln(w, "User defaults as \\fIkey\\fR = \\fIvalue\\fR lines, keyed by long flag names")
+	fmt.Fprintln(w, "(\\fI~/.config/ascii\\-art/config\\fR when \\fBXDG_CONFIG_HOME\\fR is unset).")
+	fmt.Fprintln(w, ".TP")
+	fmt.Fprintf(w, ".I %s\n", roffEscape(projectConfigName))
+	fmt.Fprintln(w, "Project defaults, looked up in the current directory and its parents;")
+	fmt.Fprintln(w, "they override the user file and are overridden by the environment and flags.")
+
+	fmt.Fprintln(w, ".SH EXIT STATUS")
+	for _, status := range exitStatuses {
+		fmt.Fprintf(w, ".TP\n.B %d\n%s\n", status.code, roffEscape(status.meaning))
+	}
+}
@@ -0,0 +1,37 @@
+package main
+
+import "strings"
+
+// lineEndOptions управляет обработкой концов строк результата
+type lineEndOptions struct {
+	showEnds     bool // отмечать конец каждой строки символом $, как `cat -e`
+	trimTrailing bool // удалять хвостовые пробелы в каждой строке
+}
+
+// applyLineEnds применяет настройки концов строк к готовому ASCII-арту.
+// С --show-ends ширина каждой строки глифов видна явно, включая хвостовые пробелы.
+func applyLineEnds(text string, opts lineEndOptions) string {
+	if !opts.showEnds && !opts.trimTrailing {
+		return text
+	}
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		last := i == len(lines)-1 // после последнего \n может остаться только хвост без переноса
+		if last && line == "" {
+			break
+		}
+		if opts.trimTrailing {
+			line = trimTrailing(line)
+		}
+		if opts.showEnds && !last {
+			line += "$"
+		}
+		lines[i] = line
+	}
+	return strings.Join(lines, "\n")
+}
+
+// trimTrailing удаляет хвостовые пробелы и табуляции строки
+func trimTrailing(line string) string {
+	return strings.TrimRight(line, " \t")
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+// Коды завершения, по которым скрипты различают класс ошибки
+const (
+	exitUsage       = 2 // неверные аргументы или флаги
+	exitFontMissing = 3 // шрифт не найден
+	exitFontInvalid = 4 // файл шрифта повреждён
+	exitUnsupported = 5 // в тексте есть символы, которых нет в шрифте
+	exitIO          = 6 // ошибка чтения или записи
+)
+
+// exitStatuses описывает коды завершения для man-страницы
+var exitStatuses = []struct {
+	code    int
+	meaning string
+}{
+	{0, "Success."},
+	{exitUsage, "Invalid arguments or flags."},
+	{exitFontMissing, "The font was not found."},
+	{exitFontInvalid, "The font file is malformed."},
+	{exitUnsupported, "The text has characters missing from the font (with --unsupported=error)."},
+	{exitIO, "A file or stream could not be read or written."},
+}
+
+// errInvalidFont оборачивает ошибки разбора файла шрифта
+var errInvalidFont = message("invalid font file")
+
+// exitError — ошибка, которая знает свой код завершения
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string { return e.err.Error() }
+func (e *exitError) Unwrap() error { return e.err }
+
+// withExitCode помечает ошибку кодом завершения
+func withExitCode(code int, err error) error {
+	return &exitError{code: code, err: err}
+}
+
+// exitCodeFor возвращает код завершения для ошибки; неотмеченные ошибки считаются ошибками ввода-вывода
+func exitCodeFor(err error) int {
+	var e *exitError
+	if errors.As(err, &e) {
+		return e.code
+	}
+	return exitIO
+}
+
+// fontExitCode определяет код завершения по ошибке загрузки шрифта
+func fontExitCode(err error) int {
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return exitFontMissing
+	case errors.Is(err, errInvalidFont):
+		return exitFontInvalid
+	}
+	return exitIO
+}
+
+// warn выводит предупреждение в stderr, не прерывая работу
+func warn(format string, args ...any) {
+	fmt.Fprintln(os.Stderr, fmt.Sprintf(tr(format), args...))
+}
+
+// printError выводит сообщение об ошибке в stderr, не завершая программу
+func printError(format string, args ...any) {
+	fmt.Fprintln(os.Stderr, tr("Error:"), fmt.Sprintf(tr(format), args...))
+}
+
+// fail выводит сообщение об ошибке в stderr и завершает программу с кодом code
+func fail(code int, format string, args ...any) {
+	printError(format, args...)
+	os.Exit(code)
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// flagSpec описывает один флаг командной строки. Описание служит единственным
+// источником для справки, man-страницы и скриптов дополнения командной строки.
+// Короткий псевдоним во всех программах набора означает один и тот же флаг.
+type flagSpec struct {
+	name     string                   // длинное имя без "--"
+	short    string                   // короткий псевдоним без "-", может быть пустым
+	hasValue bool                     // флаг принимает значение (--name=value или --name value)
+	arg      string                   // название значения в справке: N, FILE, NAME
+	usage    string                   // описание для справки; строки разделены \n
+	values   []string                 // допустимые значения для дополнения
+	complete string                   // что ещё дополнять в значении: completeFont, completeFile, completeDir
+	set      func(value string) error // сохраняет значение; для флагов без значения value пустое
+}
+
+// Виды значений, которые дополняются не из списка values
+const (
+	completeFont = "font" // имена найденных шрифтов
+	completeFile = "file" // путь к файлу
+	completeDir  = "dir"  // каталог
+)
+
+// parseFlags разбирает флаги в любом порядке вперемешку с позиционными аргументами.
+// Поддерживаются --name=value, --name value, короткие псевдонимы -n value и -n=value,
+// а "--" завершает флаги: всё после него считается позиционными аргументами.
+// Одиночный "-" — позиционный аргумент. Ошибки называют неправильный аргумент.
+func parseFlags(args []string, specs []flagSpec) ([]string, error) {
+	var positional []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			return append(positional, args[i+1:]...), nil
+		}
+		if arg == "-" || !strings.HasPrefix(arg, "-") {
+			positional = append(positional, arg)
+			continue
+		}
+
+		name, value, hasInline := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		long := strings.HasPrefix(arg, "--")
+		spec := findFlag(specs, name, long)
+		if spec == nil {
+			return nil, errorf("unknown flag %q (use -- before text that starts with '-')", arg)
+		}
+
+		flagName := "--" + spec.name
+		if !spec.hasValue {
+			if hasInline {
+				return nil, errorf("flag %s does not take a value (got %q)", flagName, arg)
+			}
+		} else if !hasInline {
+			if i+1 >= len(args) {
+				return nil, errorf("flag %s needs a value", flagName)
+			}
+			i++
+			value = args[i]
+		}
+		if err := spec.set(value); err != nil {
+			if !spec.hasValue {
+				return nil, errorf("%s: %v", flagName, err)
+			}
+			return nil, errorf("invalid value %q for %s: %v", value, flagName, err)
+		}
+	}
+	return positional, nil
+}
+
+// findFlag ищет описание флага по длинному или короткому имени
+func findFlag(specs []flagSpec, name string, long bool) *flagSpec {
+	for i := range specs {
+		if (long && specs[i].name == name) || (!long && specs[i].short != "" && specs[i].short == name) {
+			return &specs[i]
+		}
+	}
+	return nil
+}
+
+// flagLabel возвращает флаг в том виде, в каком он показан в справке: "-w, --width=N"
+func flagLabel(spec flagSpec) string {
+	label := "    --" + spec.name
+	if spec.short != "" {
+		label = "-" + spec.short + ", --" + spec.name
+	}
+	if spec.hasValue {
+		label += "=" + spec.arg
+	}
+	return label
+}
+
+// printFlags печатает справку по флагам в две колонки; описания начинаются с колонки column
+func printFlags(w io.Writer, specs []flagSpec, column int) {
+	for _, spec := range specs {
+		printEntry(w, column, flagLabel(spec), spec.usage)
+	}
+}
+
+// printEntry печатает строку справки: метку и описание, продолжения описания
+// выравниваются по колонке; слишком длинная метка занимает отдельную строку
+func printEntry(w io.Writer, column int, label, usage string) {
+	lines := strings.Split(tr(usage), "\n")
+	if len(label) >= column {
+		fmt.Fprintf(w, "  %s\n", label)
+		label = ""
+	}
+	fmt.Fprintf(w, "  %-*s%s\n", column, label, lines[0])
+	for _, line := range lines[1:] {
+		fmt.Fprintf(w, "  %-*s%s\n", column, "", line)
+	}
+}
+
+// setTrue возвращает обработчик флага без значения, включающий *dst
+func setTrue(dst *bool) func(string) error {
+	return func(string) error {
+		*dst = true
+		return nil
+	}
+}
+
+// setAction возвращает обработчик флага, который вместо рендеринга выполняет
+// действие name. Действия исключают друг друга, поэтому задать можно только одно.
+func setAction(dst *string, name string) func(string) error {
+	return func(string) error {
+		if *dst != "" && *dst != name {
+			return errorf("cannot be combined with --%s", *dst)
+		}
+		*dst = name
+		return nil
+	}
+}
+
+// setInt возвращает обработчик, сохраняющий целое число не меньше minValue
+func setInt(dst *int, minValue int) func(string) error {
+	return func(value string) error {
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return errorf("not a number")
+		}
+		if n < minValue {
+			return errorf("must be at least %d", minValue)
+		}
+		*dst = n
+		return nil
+	}
+}
+
+// setOneOf возвращает обработчик, принимающий только одно из допустимых значений
+func setOneOf(dst *string, allowed ...string) func(string) error {
+	return func(value string) error {
+		for _, a := range allowed {
+			if value == a {
+				*dst = value
+				return nil
+			}
+		}
+		return errorf("must be one of %s", strings.Join(allowed, ", "))
+	}
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"html"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Поддерживаемые форматы вывода
+const (
+	formatPlain = "plain"
+	formatHTML  = "html"
+	formatANSI  = "ansi"
+)
+
+// stdoutPath — специальное имя назначения, означающее стандартный вывод
+const stdoutPath = "-"
+
+// Константы ANSI для цветов (те же, что в программе color)
+const (
+	reset  = "\033[0m"
+	red    = "\033[31m"
+	orange = "\033[38;5;208m"
+	yellow = "\033[33m"
+	green  = "\033[32m"
+	blue   = "\033[34m"
+	indigo = "\033[38;5;54m"
+	violet = "\033[35m"
+	purple = "\033[35m"
+	cyan   = "\033[36m"
+	white  = "\033[37m"
+)
+
+// colorCodes связывает названия цветов с их ANSI-кодами
+var colorCodes = map[string]string{
+	"red":    red,
+	"orange": orange,
+	"yellow": yellow,
+	"green":  green,
+	"blue":   blue,
+	"indigo": indigo,
+	"violet": violet,
+	"purple": purple,
+	"cyan":   cyan,
+	"white":  white,
+}
+
+// colorNames возвращает названия цветов по алфавиту
+func colorNames() []string {
+	names := make([]string, 0, len(colorCodes))
+	for name := range colorCodes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// destination описывает одно место назначения вывода, его формат и обёртку
+type destination struct {
+	path   string
+	format string // пусто — формат по умолчанию (--format)
+	wrap   string // markdown или стиль комментариев, пусто — без обёртки
+	gzip   bool   // сжимать результат gzip (путь оканчивается на .gz)
+}
+
+// formatByExt определяет формат по расширению файла
+var formatByExt = map[string]string{
+	".txt":  formatPlain,
+	".html": formatHTML,
+	".htm":  formatHTML,
+	".ans":  formatANSI,
+}
+
+// parseDestination разбирает значение --output.
+// Формат или обёртку можно указать явно префиксом ("html:-", "sql:banner.out"),
+// иначе они определяются по расширению файла; для stdout и неизвестных расширений
+// формат остаётся пустым и берётся из --format (по умолчанию plain).
+// Путь с расширением .gz сжимается, а формат берётся из расширения перед ним (banner.html.gz).
+func parseDestination(spec string) (destination, error) {
+	dest := destination{path: spec}
+	if prefix, path, ok := strings.Cut(spec, ":"); ok {
+		switch {
+		case prefix == formatPlain || prefix == formatHTML || prefix == formatANSI:
+			dest = destination{path: path, format: prefix}
+		case isValidWrap(prefix):
+			dest = destination{path: path, format: formatPlain, wrap: prefix}
+		default:
+			prefix = "" // двоеточие — часть имени файла
+		}
+		if prefix != "" && path == "" {
+			return destination{}, errorf("missing path in --output=%s", spec)
+		}
+		if prefix != "" {
+			dest.gzip = strings.EqualFold(filepath.Ext(path), ".gz")
+			return dest, nil
+		}
+	}
+
+	name := spec
+	if strings.EqualFold(filepath.Ext(name), ".gz") {
+		dest.gzip = true
+		name = name[:len(name)-len(".gz")]
+	}
+	ext := strings.ToLower(filepath.Ext(name))
+	if wrap, ok := wrapByExt[ext]; ok {
+		dest.wrap = wrap
+	} else if format, ok := formatByExt[ext]; ok {
+		dest.format = format
+	}
+	return dest, nil
+}
+
+// renderDestination готовит содержимое для места назначения:
+// оборачивает арт, оформляет его в нужном формате и при необходимости сжимает
+func renderDestination(art string, dest destination, opts options) ([]byte, error) {
+	if dest.format == "" {
+		dest.format = opts.format
+	}
+	// --wrap действует на места назначения без собственной обёртки в формате plain
+	if dest.wrap == "" && dest.format == formatPlain {
+		dest.wrap = opts.wrap
+	}
+	wrapped, err := wrapOutput(art, dest.wrap)
+	if err != nil {
+		return nil, err
+	}
+	data := []byte(formatOutput(wrapped, dest.format, opts.color))
+	if !dest.gzip {
+		return data, nil
+	}
+	return compressOutput(data, dest.path)
+}
+
+// compressOutput сжимает содержимое gzip; в заголовок записывается имя файла без .gz
+func compressOutput(data []byte, path string) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	zw.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	if _, err := zw.Write(data); err != nil {
+		return nil, errorf("failed to compress %s: %v", path, err)
+	}
+	if err := zw.Close(); err != nil {
+		return nil, errorf("failed to compress %s: %v", path, err)
+	}
+	return buf.Bytes(), nil
+}
+
+// formatOutput оформляет готовый ASCII-арт в нужном формате
+func formatOutput(art, format, color string) string {
+	switch format {
+	case formatHTML:
+		var b strings.Builder
+		b.WriteString("<!DOCTYPE html>\n<html>\n<head><meta charset=\"utf-8\"></head>\n<body>\n")
+		if color != "" {
+			fmt.Fprintf(&b, "<pre style=\"color: %s\">\n", color)
+		} else {
+			b.WriteString("<pre>\n")
+		}
+		b.WriteString(html.EscapeString(art))
+		b.WriteString("</pre>\n</body>\n</html>\n")
+		return b.String()
+	case formatANSI:
+		code, ok := colorCodes[color]
+		if !ok || art == "" {
+			return art
+		}
+		// Окрашиваем каждую строку отдельно, чтобы цвет не «протекал» между строками
+		lines := strings.Split(strings.TrimSuffix(art, "\n"), "\n")
+		for i, line := range lines {
+			if line != "" {
+				lines[i] = code + line + reset
+			}
+		}
+		return strings.Join(lines, "\n") + "\n"
+	default:
+		return art
+	}
+}
@@ -0,0 +1,89 @@
+package main
+
+import "unicode/utf8"
+
+// span — диапазон байтов [start, end) внутри строки входного текста
+type span struct {
+	start, end int
+}
+
+// glyphWidth возвращает ширину ASCII-арта символа; символы без глифа не выводятся
+func (a *ASCIIArt) glyphWidth(r rune) int {
+	if art, ok := a.chars[r]; ok {
+		return displayWidth(art.lines[0])
+	}
+	return 0
+}
+
+// textWidth возвращает ширину ASCII-арта строки текста
+func (a *ASCIIArt) textWidth(text string) int {
+	width := 0
+	for _, r := range text {
+		width += a.glyphWidth(r)
+	}
+	return width
+}
+
+// wrapLine разбивает строку текста на части, ASCII-арт каждой из которых
+// помещается в width колонок. Разрыв делается по пробелам между словами,
+// а слово шире width режется посимвольно. Пробелы в месте разрыва отбрасываются.
+// При width <= 0 строка не переносится.
+func (a *ASCIIArt) wrapLine(line string, width int) []span {
+	if width <= 0 || a.textWidth(line) <= width {
+		return []span{{0, len(line)}}
+	}
+
+	var spans []span
+	cur := span{0, 0} // текущая строка; ведущие пробелы первой строки сохраняются
+	curWidth := 0
+	for i := 0; i < len(line); {
+		if line[i] == ' ' {
+			i++
+			continue
+		}
+		j := i
+		for j < len(line) && line[j] != ' ' {
+			j++
+		}
+		wordWidth := a.textWidth(line[i:j])
+
+		// Пробуем дописать слово к текущей строке вместе с пробелами перед ним
+		gap := a.textWidth(line[cur.end:i])
+		if curWidth+gap+wordWidth <= width {
+			cur.end = j
+			curWidth += gap + wordWidth
+			i = j
+			continue
+		}
+		if cur.end > cur.start {
+			spans = append(spans, cur)
+		}
+
+		// Слово начинает новую строку; слишком длинное режем посимвольно
+		for wordWidth > width {
+			k, w := i, 0
+			for k < j {
+				r, size := utf8.DecodeRuneInString(line[k:])
+				gw := a.glyphWidth(r)
+				if w+gw > width && k > i {
+					break
+				}
+				w += gw
+				k += size
+			}
+			spans = append(spans, span{i, k})
+			i = k
+			wordWidth -= w
+		}
+		cur = span{i, j}
+		curWidth = wordWidth
+		i = j
+	}
+	if cur.end > cur.start {
+		spans = append(spans, cur)
+	}
+	if len(spans) == 0 {
+		return []span{{0, len(line)}}
+	}
+	return spans
+}
@@ -2,7 +2,9 @@ package main
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 )
@@ -15,6 +17,7 @@ type ASCIIChar struct {
 // ASCIIArt - основная структура, которая хранит весь шрифт
 type ASCIIArt struct {
 	chars map[rune]ASCIIChar // Карта, связывающая каждый символ (руну) с его ASCII-представлением
+	width int                // Ширина переноса строк в колонках, 0 — без переноса
 }
 
 // NewASCIIArt - создает новый экземпляр структуры
@@ -28,7 +31,7 @@ func NewASCIIArt() *ASCIIArt {
 func (a *ASCIIArt) LoadFont(filename string) error {
 	file, err := os.Open(filename)
 	if err != nil {
-		return fmt.Errorf("failed to open %s: %v", filename, err)
+		return errorf("failed to open %s: %w", filename, err)
 	}
 	defer file.Close()
 	// Список всех поддерживаемых символов в порядке ASCII
@@ -52,6 +55,9 @@ func (a *ASCIIArt) LoadFont(filename string) error {
 		line := scanner.Text()
 		if line == "" {
 			if lineIndex > 0 && charIndex < len(supportedChars) {
+				if lineIndex < 8 {
+					return errorf("%w %s: character %q has %d lines instead of 8", errInvalidFont, filename, supportedChars[charIndex], lineIndex)
+				}
 				a.chars[supportedChars[charIndex]] = ASCIIChar{lines: currentLines}
 				charIndex++
 				lineIndex = 0
@@ -61,12 +67,25 @@ func (a *ASCIIArt) LoadFont(filename string) error {
 			if lineIndex < 8 {
 				currentLines[lineIndex] = line
 				lineIndex++
+			} else if charIndex < len(supportedChars) {
+				return errorf("%w %s: character %q has more than 8 lines", errInvalidFont, filename, supportedChars[charIndex])
 			}
 		}
 	}
 	// Обрабатываем последний символ в файле
 	if lineIndex > 0 && charIndex < len(supportedChars) {
+		if lineIndex < 8 {
+			return errorf("%w %s: character %q has %d lines instead of 8", errInvalidFont, filename, supportedChars[charIndex], lineIndex)
+		}
 		a.chars[supportedChars[charIndex]] = ASCIIChar{lines: currentLines}
+		charIndex++
+	}
+	if err := scanner.Err(); err != nil {
+		return errorf("failed to read %s: %w", filename, err)
+	}
+	// Шрифт должен содержать все символы от пробела до тильды
+	if charIndex < len(supportedChars) {
+		return errorf("%w %s: %d of %d characters", errInvalidFont, filename, charIndex, len(supportedChars))
 	}
 	return nil
 }
@@ -79,11 +98,11 @@ func (a *ASCIIArt) RenderText(input string) string {
 		return ""
 	}
 
-	if input == "\\n" {
+	if input == "\n" {
 		return ""
 	}
 
-	lines := strings.Split(input, "\\n")
+	lines := strings.Split(input, "\n")
 
 	for i, line := range lines {
 		if line == "" { // Обработка пустых строк
@@ -91,21 +110,24 @@ func (a *ASCIIArt) RenderText(input string) string {
 			continue
 		}
 
-		artLines := [8]string{}
-		for _, char := range line {
-			if art, exists := a.chars[char]; exists {
-				for j := 0; j < 8; j++ {
-					artLines[j] += art.lines[j]
+		// Переносим строку по словам, если её ASCII-арт не помещается в ширину
+		for _, part := range a.wrapLine(line, a.width) {
+			artLines := [8]string{}
+			for _, char := range line[part.start:part.end] {
+				if art, exists := a.chars[char]; exists {
+					for j := 0; j < 8; j++ {
+						artLines[j] += art.lines[j]
+					}
 				}
 			}
-		}
-		// Добавляем строки ASCII-арта, соединяя переносами строк
-		for j := 0; j < 8; j++ {
-			result.WriteString(artLines[j])
-			result.WriteString("\n")
+			// Добавляем строки ASCII-арта, соединяя переносами строк
+			for j := 0; j < 8; j++ {
+				result.WriteString(artLines[j])
+				result.WriteString("\n")
+			}
 		}
 		// Обрабатываем пробелы между блоками текста
-		if i < len(lines)-1 || (len(input) >= 2 && input[len(input)-2:] == "\\n") {
+		if i < len(lines)-1 || strings.HasSuffix(input, "\n") {
 			result.WriteString("")
 		}
 	}
@@ -113,82 +135,364 @@ func (a *ASCIIArt) RenderText(input string) string {
 }
 
 func main() {
-	// Проверяем есть ли необходимое нам число аргументов (2-4)
-	if len(os.Args) < 2 || len(os.Args) > 4 {
-		printUsage()
+	// Язык сообщений выбирается до разбора флагов (см. messages.go)
+	lang = detectLang(os.Args[1:])
+	// Ошибки выводятся в stderr, код завершения зависит от класса ошибки (см. exit.go)
+	opts, args, err := parseArgs(os.Args[1:])
+	if opts.help && err == nil {
+		printUsage(os.Stdout)
 		return
 	}
-
-	var outputFile string
-	var text, bannerType string
-
-	// Разбор аргументов командной строки в зависимости от их количества
-	switch len(os.Args) {
-	case 2:
-		// Случай с двумя аргументами (только строка)
-		text = os.Args[1]
-		bannerType = "standard"
-
-	case 3:
-		// Случай с тремя аргументами (может быть либо флаг --output + строка, либо строка + тип баннера)
-		if strings.HasPrefix(os.Args[1], "--output=") {
-			if !isValidOutputFlag(os.Args[1]) {
-				printUsage() // Если флаг некорректный, выводим инструкцию по использованию
-				return
+	if err == nil {
+		err = opts.configErr
+	}
+	// В пакетном режиме тексты берутся из файла, а аргументом можно задать баннер по умолчанию
+	if err == nil && opts.batchFile != "" && len(args) <= 1 {
+		bannerType := opts.banner
+		if len(args) == 1 {
+			bannerType = args[0]
+		}
+		if err := runBatch(opts, bannerType); err != nil {
+			fail(exitCodeFor(err), "%v", err)
+		}
+		return
+	}
+	// config show — то же, что --show-config
+	if err == nil && opts.action == "" && isConfigShow(os.Args[1:], args) {
+		opts.action, args = "show-config", nil
+	}
+	// --showcase выполняется вместо рендеринга и принимает только строку текста
+	if err == nil && opts.action == "showcase" {
+		if len(args) > 1 {
+			fail(exitUsage, "unexpected argument %q", args[1])
+		}
+		os.Exit(runShowcase(opts, args))
+	}
+	// Остальные флаги действий выполняются вместо рендеринга и не принимают текста
+	if err == nil && opts.action != "" {
+		if len(args) > 0 {
+			fail(exitUsage, "unexpected argument %q", args[0])
+		}
+		switch opts.action {
+		case "show-config":
+			showConfig(os.Stdout, opts.settings)
+		case "completion":
+			writeCompletion(os.Stdout, opts.shell, programName(), (&options{}).flagSpecs())
+		case "man":
+			writeManPage(os.Stdout, programName(), (&options{}).flagSpecs())
+		case "list-fonts":
+			for _, font := range discoverFonts(opts.fontPath) {
+				fmt.Println(font.name)
 			}
-			// Извлекаем имя файла для вывода (удаляем префикс "--output=")
-			outputFile = strings.TrimPrefix(os.Args[1], "--output=")
-			text = os.Args[2]
-			// Тип баннера по умолчанию
-			bannerType = "standard"
-		} else {
-			// Если флаг не присутствует, то первый аргумент — это текст, второй — тип баннера
-			text = os.Args[1]
-			bannerType = os.Args[2]
 		}
-
-	case 4:
-		// Случай с четырьмя аргументами (--output флаг + строка + тип баннера)
-		if !isValidOutputFlag(os.Args[1]) {
-			printUsage()
-			return
+		return
+	}
+	// Текст берётся из аргумента, из файла --input или из stdin
+	text, ok := "", false
+	if err == nil {
+		if text, args, ok, err = inputText(opts.input, args); err != nil {
+			fail(exitIO, "%v", err)
+		}
+	}
+	if err == nil && len(args) > 1 {
+		err = errorf("unexpected argument %q", args[1])
+	}
+	// Проверяем есть ли необходимое нам число аргументов (строка и, возможно, тип баннера)
+	if err != nil || !ok {
+		if err != nil {
+			printError("%v", err)
 		}
-		// Извлекаем имя файла для вывода
-		outputFile = strings.TrimPrefix(os.Args[1], "--output=")
-		text = os.Args[2]
-		bannerType = os.Args[3]
+		printUsage(os.Stderr)
+		os.Exit(exitUsage)
+	}
+
+	bannerType := opts.banner
+	if len(args) == 1 {
+		bannerType = args[0]
 	}
+	fontFile := resolveFont(bannerType+".txt", opts.fontPath)
 
 	// Создаем новый процессор для ASCII-арта и загружаем шрифт
 	ascii := NewASCIIArt()
-	if err := ascii.LoadFont(bannerType + ".txt"); err != nil {
-		fmt.Printf("Ошибка при загрузке шрифта: %v\n", err)
-		return
+	if err := ascii.LoadFont(fontFile); err != nil {
+		fail(fontExitCode(err), "loading font file: %v", err)
+	}
+
+	// Символы, которых нет в шрифте, обрабатываем по политике --unsupported
+	if text, err = ascii.applyCharPolicy(text, opts.chars); err != nil {
+		fail(exitUnsupported, "%v", err)
 	}
 
 	// Генерируем ASCII-арт для заданного текста
-	output := ascii.RenderText(text)
+	ascii.width = opts.wrapWidth()
+	output := applyLineEnds(ascii.RenderText(text), opts.ends)
+
+	// Без --output печатаем результат на экран
+	if len(opts.outputs) == 0 {
+		opts.outputs = []destination{{path: stdoutPath}}
+	}
+	// Загруженный шрифт нельзя затирать результатом
+	opts.write.protected = append(opts.write.protected, fontFile)
+
+	os.Exit(writeDestinations(opts, func(dest destination) ([]byte, error) {
+		return renderDestination(output, dest, opts)
+	}))
+}
 
-	// Обрабатываем вывод в зависимости от того, указан ли файл для вывода
-	if outputFile != "" {
-		err := os.WriteFile(outputFile, []byte(output), 0644)
+// writeDestinations отправляет один и тот же результат во все места назначения
+// (как tee) и возвращает код завершения: ошибка одного назначения не мешает
+// остальным, но меняет код. render готовит содержимое для каждого назначения.
+func writeDestinations(opts options, render func(destination) ([]byte, error)) int {
+	status := 0
+	for _, dest := range opts.outputs {
+		data, err := render(dest)
 		if err != nil {
-			fmt.Printf("Ошибка при записи в файл: %v\n", err)
-			return
+			printError("%v", err)
+			status = exitIO
+			continue
 		}
-	} else {
-		fmt.Print(output)
+		if dest.path == stdoutPath {
+			if _, err := os.Stdout.Write(data); err != nil {
+				printError("writing to stdout: %v", err)
+				status = exitIO
+			}
+			continue
+		}
+		err = writeOutput(dest.path, data, opts.write)
+		if errors.Is(err, errSkipped) {
+			warn("File %s already exists, skipped", dest.path)
+			continue
+		}
+		if err != nil {
+			printError("writing to file: %v", err)
+			status = exitIO
+		}
+	}
+	return status
+}
+
+// options хранит разобранные флаги командной строки
+type options struct {
+	outputs   []destination          // места назначения вывода, "-" означает stdout
+	color     string                 // цвет для форматов html и ansi
+	wrap      string                 // обёртка по умолчанию: markdown или стиль комментариев
+	write     writeOptions           // политика записи в файл
+	ends      lineEndOptions         // отметка концов строк и удаление хвостовых пробелов
+	batchFile string                 // файл со списком баннеров для пакетного режима
+	archive   string                 // архив (.tar, .tar.gz, .tgz, .zip) для результатов пакетного режима
+	width     int                    // ширина переноса: 0 — по терминалу, -1 — без переноса
+	banner    string                 // баннер из --banner (по умолчанию standard)
+	input     string                 // файл с текстом из --input, "-" — стандартный ввод
+	chars     charPolicy             // обработка символов, которых нет в шрифте
+	format    string                 // формат для stdout и файлов, формат которых не определён
+	fontPath  string                 // каталоги для поиска шрифтов через разделитель списка путей
+	settings  map[string]configEntry // действующие настройки для --show-config
+	configErr error                  // ошибка в файлах настроек или ASCII_ART_*
+	action    string                 // флаг, который выполняется вместо рендеринга, например showcase
+	shell     string                 // оболочка для --completion
+	noWrap    bool                   // не переносить длинные строки
+	help      bool
+}
+
+// flagSpecs описывает флаги программы; обработчики сохраняют значения в opts
+func (opts *options) flagSpecs() []flagSpec {
+	policies := []string{policySkip, policyError, policyPlaceholder, policyTranslit}
+	formats := []string{formatPlain, formatHTML, formatANSI}
+	return []flagSpec{
+		{name: "output", short: "o", hasValue: true, arg: "FILE", complete: completeFile,
+			usage: "write the result to a file; repeat for several files, '-' is stdout\nformat follows the extension (.txt, .html, .ans) or a prefix (html:-)",
+			set: func(value string) error {
+				if value == "" {
+					return errorf("missing path")
+				}
+				dest, err := parseDestination(value)
+				if err != nil {
+					return err
+				}
+				opts.outputs = append(opts.outputs, dest)
+				return nil
+			}},
+		{name: "color", short: "c", hasValue: true, arg: "COLOR", values: colorNames(),
+			usage: "color used by the html and ansi formats",
+			set: func(value string) error {
+				opts.color = strings.ToLower(value)
+				if _, ok := colorCodes[opts.color]; !ok {
+					return errorf("unknown color")
+				}
+				return nil
+			}},
+		{name: "format", hasValue: true, arg: "FORMAT", values: formats,
+			usage: "plain, html or ansi for stdout and files without a known extension",
+			set:   setOneOf(&opts.format, formats...)},
+		{name: "wrap", hasValue: true, arg: "STYLE", values: wrapNames(),
+			usage: "wrap plain output: markdown, go, python, sh, sql, c or //, #, --, /*",
+			set: func(value string) error {
+				opts.wrap = value
+				if !isValidWrap(value) {
+					return errorf("unknown wrapper")
+				}
+				return nil
+			}},
+		{name: "banner", short: "b", hasValue: true, arg: "NAME", complete: completeFont,
+			usage: "banner to use (default standard)", set: setString(&opts.banner)},
+		{name: "input", short: "i", hasValue: true, arg: "FILE", complete: completeFile,
+			usage: "read the text from FILE ('-' is stdin); without a STRING\nthe text is read from stdin when it is piped",
+			set:   setString(&opts.input)},
+		{name: "width", short: "w", hasValue: true, arg: "N",
+			usage: "wrap long text so the art fits N columns (default: terminal width on stdout)",
+			set:   setInt(&opts.width, 1)},
+		{name: "no-wrap", usage: "never wrap long text", set: setTrue(&opts.noWrap)},
+		{name: "unsupported", short: "u", hasValue: true, arg: "MODE", values: policies,
+			usage: "characters missing from the font: skip (default), error,\nplaceholder or translit (é→e, ß→ss, curly quotes→straight)",
+			set:   setOneOf(&opts.chars.mode, policies...)},
+		{name: "placeholder", hasValue: true, arg: "CHAR",
+			usage: "draw CHAR (or 'box') for missing characters (default '?')",
+			set: func(value string) error {
+				opts.chars.mode = policyPlaceholder
+				opts.chars.placeholder = value
+				return validatePlaceholder(value)
+			}},
+		{name: "font-path", hasValue: true, arg: "DIRS", complete: completeDir,
+			usage: "directories searched for banner files first (':'-separated)",
+			set: func(value string) error {
+				opts.fontPath = value
+				return nil
+			}},
+		{name: "show-ends", short: "E", usage: "mark the end of every line with $", set: setTrue(&opts.ends.showEnds)},
+		{name: "trim-trailing", short: "T", usage: "strip trailing spaces from every line", set: setTrue(&opts.ends.trimTrailing)},
+		{name: "batch", hasValue: true, arg: "FILE", complete: completeFile,
+			usage: "render every line of file (TEXT[<TAB>BANNER[<TAB>PATH]]) to its own file",
+			set:   setString(&opts.batchFile)},
+		{name: "archive", hasValue: true, arg: "FILE", complete: completeFile,
+			usage: "with --batch, bundle the results and an index into .tar, .tar.gz, .tgz or .zip",
+			set: func(value string) error {
+				opts.archive = value
+				if !isArchivePath(value) {
+					return errorf("unsupported archive (use .tar, .tar.gz, .tgz or .zip)")
+				}
+				return nil
+			}},
+		{name: "showcase", usage: "render STRING (default: every character) in every font",
+			set: setAction(&opts.action, "showcase")},
+		{name: "force", short: "f", usage: "overwrite the file if it already exists", set: setTrue(&opts.write.force)},
+		{name: "no-clobber", short: "n", usage: "skip writing if the file already exists", set: setTrue(&opts.write.noClobber)},
+		{name: "append", short: "a", usage: "append to the file instead of replacing it", set: setTrue(&opts.write.append)},
+		{name: "mkdir", usage: "create missing parent directories", set: setTrue(&opts.write.mkdirs)},
+		{name: "show-config", usage: "print the effective settings and where they come from",
+			set: setAction(&opts.action, "show-config")},
+		{name: "completion", hasValue: true, arg: "SHELL", values: completionShells,
+			usage: "print a completion script for bash, zsh or fish",
+			set: func(value string) error {
+				if err := setOneOf(&opts.shell, completionShells...)(value); err != nil {
+					return err
+				}
+				return setAction(&opts.action, "completion")(value)
+			}},
+		{name: "man", usage: "print the manual page in roff format", set: setAction(&opts.action, "man")},
+		{name: "list-fonts", usage: "list the fonts found in the font path and the bundled ones",
+			set: setAction(&opts.action, "list-fonts")},
+		{name: "lang", hasValue: true, arg: "LANG", values: languages,
+			usage: "language of help and messages: en or ru (default: from LC_ALL or LANG)",
+			set:   setOneOf(&lang, languages...)},
+		{name: "help", short: "h", usage: "show this help", set: setTrue(&opts.help)},
+	}
+}
+
+// setString возвращает обработчик, сохраняющий непустую строку
+func setString(dst *string) func(string) error {
+	return func(value string) error {
+		if value == "" {
+			return errorf("must not be empty")
+		}
+		*dst = value
+		return nil
+	}
+}
+
+// parseArgs разбирает флаги (в любом месте командной строки) и возвращает позиционные аргументы
+func parseArgs(args []string) (options, []string, error) {
+	opts := options{banner: "standard", format: formatPlain, chars: charPolicy{mode: policySkip, placeholder: "?"}}
+	specs := opts.flagSpecs()
+
+	// Настройки применяются от младших к старшим: встроенные, файл пользователя,
+	// файл проекта, переменные окружения и, наконец, флаги командной строки
+	opts.settings = map[string]configEntry{
+		"banner":      {"standard", "built-in"},
+		"color":       {"", "built-in"},
+		"width":       {"terminal", "built-in"},
+		"format":      {formatPlain, "built-in"},
+		"font-path":   {"", "built-in"},
+		"unsupported": {policySkip, "built-in"},
+		"placeholder": {"?", "built-in"},
+	}
+	layers, err := configLayers()
+	if err == nil {
+		err = applyConfig(specs, layers, opts.settings)
+	}
+	// Ошибку в настройках сообщает main после --help, чтобы справка
+	// открывалась и при испорченном .asciiartrc
+	opts.configErr = err
+
+	trackFlags(specs, opts.settings)
+	positional, err := parseFlags(args, specs)
+	if err != nil {
+		return opts, nil, err
+	}
+	if opts.chars.mode != opts.settings["unsupported"].value {
+		// --placeholder включает режим placeholder
+		opts.settings["unsupported"] = configEntry{opts.chars.mode, opts.settings["placeholder"].source}
+	}
+	if opts.noWrap {
+		opts.width = -1
+	}
+	if opts.write.force && opts.write.noClobber {
+		return opts, nil, errorf("--force and --no-clobber cannot be used together")
+	}
+	if opts.action != "" && opts.batchFile != "" {
+		return opts, nil, errorf("--%s cannot be used with --batch", opts.action)
 	}
+	if opts.archive != "" && opts.batchFile == "" {
+		return opts, nil, errorf("--archive requires --batch")
+	}
+	if opts.batchFile != "" && len(opts.outputs) > 0 {
+		return opts, nil, errorf("--output cannot be used with --batch")
+	}
+	if opts.batchFile == "" && !hasFileOutput(opts.outputs) && (opts.write.force || opts.write.noClobber || opts.write.append || opts.write.mkdirs) {
+		return opts, nil, errorf("file options require --output")
+	}
+	return opts, positional, nil
+}
+
+// wrapWidth возвращает ширину переноса строк. Ширина терминала учитывается,
+// только если результат выводится лишь на экран; файлы переносятся по --width.
+func (opts options) wrapWidth() int {
+	if opts.width == 0 && (hasFileOutput(opts.outputs) || opts.batchFile != "") {
+		return 0
+	}
+	return wrapWidth(opts.width)
 }
 
-// Вспомогательная функция для вывода инструкции по использованию
-func printUsage() {
-	fmt.Println("Usage: go run . [OPTION] [STRING] [BANNER]")
-	fmt.Println("\nEX: go run . --output=<fileName.txt> something standard")
+// hasFileOutput сообщает, есть ли среди мест назначения хотя бы один файл
+func hasFileOutput(outputs []destination) bool {
+	for _, dest := range outputs {
+		if dest.path != stdoutPath {
+			return true
+		}
+	}
+	return false
 }
 
-// Вспомогательная функция для проверки формата флага --output
-func isValidOutputFlag(flag string) bool {
-	// Проверка, что флаг начинается с "--output=" и имеет длину больше 9 (для наличия самого пути)
-	return strings.HasPrefix(flag, "--output=") && len(flag) > 9
+// usageSynopses — аргументы программы в строке Usage и в man-странице
+var usageSynopses = []string{"[OPTION]... [STRING|-] [BANNER]"}
+
+// usageColumn — колонка, с которой в справке начинаются описания флагов
+const usageColumn = 21
+
+// Вспомогательная функция для вывода инструкции по использованию в w
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, tr("Usage:"), "go run . "+usageSynopses[0])
+	fmt.Fprintln(w, "\n"+tr("Options (in any order, --flag=value or --flag value; -- ends options):"))
+	printFlags(w, (&options{}).flagSpecs(), usageColumn)
+	fmt.Fprintln(w, "\n"+tr("EX:"), "go run . --output=<fileName.txt> something standard")
+	fmt.Fprintln(w, "\n"+tr("Defaults for banner, color, width, format, font-path, unsupported and placeholder are\nread from $XDG_CONFIG_HOME/ascii-art/config, .asciiartrc and ASCII_ART_* variables."))
 }
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+)
+
+// languages — языки сообщений; английский — язык исходных строк
+var languages = []string{"en", "ru"}
+
+// lang — язык справки, предупреждений и ошибок. Определяется в начале main
+// по --lang или по LC_ALL, LC_MESSAGES и LANG, а флаг --lang потом проверяет значение.
+var lang = "en"
+
+// detectLang выбирает язык сообщений до разбора флагов, чтобы на нужном языке
+// были и ошибки самого разбора: сначала --lang, затем переменные локали в порядке POSIX
+func detectLang(args []string) string {
+	for i, arg := range args {
+		if arg == "--" {
+			break
+		}
+		value, ok := strings.CutPrefix(arg, "--lang=")
+		if !ok && arg == "--lang" && i+1 < len(args) {
+			value, ok = args[i+1], true
+		}
+		if ok && slices.Contains(languages, value) {
+			return value
+		}
+	}
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if locale := os.Getenv(name); locale != "" {
+			if strings.HasPrefix(strings.ToLower(locale), "ru") {
+				return "ru"
+			}
+			return "en"
+		}
+	}
+	return "en"
+}
+
+// tr возвращает сообщение на текущем языке; строки без перевода остаются английскими
+func tr(s string) string {
+	if lang == "ru" {
+		if translated, ok := russian[s]; ok {
+			return translated
+		}
+	}
+	return s
+}
+
+// errorf — fmt.Errorf с переводом формата
+func errorf(format string, args ...any) error {
+	return fmt.Errorf(tr(format), args...)
+}
+
+// message — ошибка-константа, которая переводится при выводе, поэтому её можно
+// объявить до выбора языка и сравнивать через errors.Is
+type message string
+
+func (m message) Error() string { return tr(string(m)) }
+
+// russian — перевод сообщений на русский, ключ — английская строка из кода
+var russian = map[string]string{
+	// batch.go
+	"failed to open %s: %v":                                       "не удалось открыть %s: %v",
+	"%s:%d: too many fields":                                      "%s:%d: слишком много полей",
+	"failed to read %s: %v":                                       "не удалось прочитать %s: %v",
+	"File %s already exists, skipped":                             "Файл %s уже существует, пропускаем",
+	"archive entry %s must be a relative path inside the archive": "элемент архива %s должен быть относительным путём внутри архива",
+	"duplicate archive entry %s":                                  "элемент архива %s повторяется",
+	"failed to add %s to archive: %v":                             "не удалось добавить %s в архив: %v",
+	"failed to finish archive: %v":                                "не удалось завершить архив: %v",
+
+	// config.go
+	"%s:%d: expected key = value":     "%s:%d: ожидается ключ = значение",
+	"%s:%d: unknown setting %q":       "%s:%d: неизвестная настройка %q",
+	"%s: invalid value %q for %s: %v": "%s: недопустимое значение %q для %s: %v",
+
+	// exit.go
+	"invalid font file": "повреждённый файл шрифта",
+	"Error:":            "Ошибка:",
+
+	// flags.go
+	"unknown flag %q (use -- before text that starts with '-')": "неизвестный флаг %q (перед текстом, начинающимся с '-', поставьте --)",
+	"flag %s does not take a value (got %q)":                    "флаг %s не принимает значения (получено %q)",
+	"flag %s needs a value":                                     "флагу %s нужно значение",
+	"invalid value %q for %s: %v":                               "недопустимое значение %q для %s: %v",
+	"cannot be combined with --%s":                              "нельзя использовать вместе с --%s",
+	"not a number":                                              "не число",
+	"must be at least %d":                                       "должно быть не меньше %d",
+	"must be one of %s":                                         "должно быть одним из: %s",
+
+	// format.go
+	"missing path in --output=%s": "не указан путь в --output=%s",
+	"failed to compress %s: %v":   "не удалось сжать %s: %v",
+
+	// input.go
+	"failed to read stdin: %v": "не удалось прочитать stdin: %v",
+
+	// main.go
+	"failed to open %s: %w":                         "не удалось открыть %s: %w",
+	"%w %s: character %q has %d lines instead of 8": "%w %s: у символа %q %d строк вместо 8",
+	"%w %s: character %q has more than 8 lines":     "%w %s: у символа %q больше 8 строк",
+	"failed to read %s: %w":                         "не удалось прочитать %s: %w",
+	"%w %s: %d of %d characters":                    "%w %s: %d символов из %d",
+	"unexpected argument %q":                        "лишний аргумент %q",
+	"loading font file: %v":                         "при загрузке шрифта: %v",
+	"writing to stdout: %v":                         "при записи в stdout: %v",
+	"writing to file: %v":                           "при записи в файл: %v",
+	"write the result to a file; repeat for several files, '-' is stdout\nformat follows the extension (.txt, .html, .ans) or a prefix (html:-)": "записать результат в файл; можно повторять, '-' — stdout;\nформат по расширению (.txt, .html, .ans) или по префиксу (html:-)",
+	"missing path": "не указан путь",
+	"color used by the html and ansi formats": "цвет для форматов html и ansi",
+	"unknown color": "неизвестный цвет",
+	"plain, html or ansi for stdout and files without a known extension":   "plain, html или ansi для stdout и файлов с неизвестным расширением",
+	"wrap plain output: markdown, go, python, sh, sql, c or //, #, --, /*": "обернуть текстовый вывод: markdown, go, python, sh, sql, c или //, #, --, /*",
+	"unknown wrapper":                  "неизвестная обёртка",
+	"banner to use (default standard)": "баннер (по умолчанию standard)",
+	"read the text from FILE ('-' is stdin); without a STRING\nthe text is read from stdin when it is piped": "читать текст из FILE ('-' — stdin); без STRING текст\nчитается из stdin, если ввод перенаправлен",
+	"wrap long text so the art fits N columns (default: terminal width on stdout)":                           "переносить длинный текст, чтобы арт помещался в N колонок (по умолчанию ширина терминала)",
+	"never wrap long text": "никогда не переносить длинный текст",
+	"characters missing from the font: skip (default), error,\nplaceholder or translit (é→e, ß→ss, curly quotes→straight)": "символы, которых нет в шрифте: skip (по умолчанию), error,\nplaceholder или translit (é→e, ß→ss, фигурные кавычки→прямые)",
+	"draw CHAR (or 'box') for missing characters (default '?')":                                                            "рисовать CHAR (или 'box') вместо отсутствующих (по умолчанию '?')",
+	"directories searched for banner files first (':'-separated)":                                                          "каталоги, где сначала ищутся файлы баннеров (через ':')",
+	"mark the end of every line with $":                                                                                    "отметить конец каждой строки знаком $",
+	"strip trailing spaces from every line":                                                                                "удалить пробелы в конце каждой строки",
+	"render every line of file (TEXT[<TAB>BANNER[<TAB>PATH]]) to its own file":                                             "нарисовать каждую строку файла (TEXT[<TAB>BANNER[<TAB>PATH]]) в отдельный файл",
+	"with --batch, bundle the results and an index into .tar, .tar.gz, .tgz or .zip":                                       "с --batch — собрать результаты и оглавление в .tar, .tar.gz, .tgz или .zip",
+	"unsupported archive (use .tar, .tar.gz, .tgz or .zip)":                                                                "неподдерживаемый архив (используйте .tar, .tar.gz, .tgz или .zip)",
+	"render STRING (default: every character) in every font":                                                               "нарисовать STRING (по умолчанию все символы) каждым шрифтом",
+	"overwrite the file if it already exists":                                                                              "перезаписать файл, если он уже существует",
+	"skip writing if the file already exists":                                                                              "не записывать, если файл уже существует",
+	"append to the file instead of replacing it":                                                                           "дописать в конец файла вместо замены",
+	"create missing parent directories":                                                                                    "создать недостающие родительские каталоги",
+	"print the effective settings and where they come from":                                                                "вывести действующие настройки и их источники",
+	"print a completion script for bash, zsh or fish":                                                                      "вывести скрипт дополнения для bash, zsh или fish",
+	"print the manual page in roff format":                                                                                 "вывести man-страницу в формате roff",
+	"list the fonts found in the font path and the bundled ones":                                                           "вывести шрифты из font-path и встроенные",
+	"language of help and messages: en or ru (default: from LC_ALL or LANG)":                                               "язык справки и сообщений: en или ru (по умолчанию из LC_ALL или LANG)",
+	"show this help":    "показать эту справку",
+	"must not be empty": "не должно быть пустым",
+	"--force and --no-clobber cannot be used together": "--force и --no-clobber нельзя использовать вместе",
+	"--%s cannot be used with --batch":                 "--%s нельзя использовать с --batch",
+	"--archive requires --batch":                       "--archive требует --batch",
+	"--output cannot be used with --batch":             "--output нельзя использовать с --batch",
+	"file options require --output":                    "флаги записи в файл требуют --output",
+	"Usage:":                                           "Использование:",
+	"Options (in any order, --flag=value or --flag value; -- ends options):": "Флаги (в любом порядке, --флаг=значение или --флаг значение; -- завершает флаги):",
+	"EX:": "Пример:",
+	"Defaults for banner, color, width, format, font-path, unsupported and placeholder are\nread from $XDG_CONFIG_HOME/ascii-art/config, .asciiartrc and ASCII_ART_* variables.": "Значения по умолчанию для banner, color, width, format, font-path, unsupported и placeholder\nберутся из $XDG_CONFIG_HOME/ascii-art/config, .asciiartrc и переменных ASCII_ART_*.",
+
+	// showcase.go
+	"no fonts found":      "шрифты не найдены",
+	"height %d, width %d": "высота %d, ширина %d",
+	"Font showcase":       "Образцы шрифтов",
+
+	// unsupported.go
+	"unsupported characters":                "неподдерживаемые символы",
+	"must be a single character or %q":      "должен быть одним символом или %q",
+	"%w: placeholder %q is not in the font": "%w: заменителя %q нет в шрифте",
+	"%q (%U) at %d:%d":                      "%q (%U) в позиции %d:%d",
+
+	// wrap.go
+	"unknown wrapper %s": "неизвестная обёртка %s",
+
+	// write.go
+	"file exists, skipped (--no-clobber)":                    "файл существует, пропущен (--no-clobber)",
+	"empty output path":                                      "пустой путь вывода",
+	"output path %s is a directory":                          "путь вывода %s — каталог",
+	"refusing to overwrite font file %s":                     "файл шрифта %s перезаписывать нельзя",
+	"failed to create directory %s: %v":                      "не удалось создать каталог %s: %v",
+	"directory %s does not exist (use --mkdir to create it)": "каталог %s не существует (создать его можно с --mkdir)",
+	"file %s already exists (use --force to overwrite)":      "файл %s уже существует (перезаписать можно с --force)",
+	"failed to write %s: %v":                                 "не удалось записать %s: %v",
+	"failed to create temporary file: %v":                    "не удалось создать временный файл: %v",
+	"failed to sync %s: %v":                                  "не удалось сбросить %s на диск: %v",
+	"failed to chmod %s: %v":                                 "не удалось изменить права %s: %v",
+	"failed to close %s: %v":                                 "не удалось закрыть %s: %v",
+	"failed to rename %s to %s: %v":                          "не удалось переименовать %s в %s: %v",
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"fmt"
+	"html"
+	"strings"
+)
+
+// showcaseCharset — текст образца по умолчанию: все символы шрифта, по строкам
+const showcaseCharset = " !\"#$%&'()*+,-./\n0123456789:;<=>?@\nABCDEFGHIJKLM\nNOPQRSTUVWXYZ\n[\\]^_`{|}~\nabcdefghijklm\nnopqrstuvwxyz"
+
+// showcaseEntry — образец одного шрифта: имя, размеры арта и сам арт
+type showcaseEntry struct {
+	font   fontInfo
+	art    string
+	height int // строк в арте
+	width  int // колонок в самой длинной строке
+}
+
+// runShowcase печатает текст (или все символы) каждым найденным шрифтом и
+// отправляет результат во все места назначения --output. Возвращает код завершения.
+func runShowcase(opts options, args []string) int {
+	text, _, ok, err := inputText(opts.input, args)
+	if err != nil {
+		fail(exitIO, "%v", err)
+	}
+	if !ok || text == "" {
+		text = showcaseCharset
+	}
+
+	fonts := discoverFonts(opts.fontPath)
+	if len(fonts) == 0 {
+		fail(exitFontMissing, "no fonts found")
+	}
+	status := 0
+	var entries []showcaseEntry
+	for _, font := range fonts {
+		entry, err := renderShowcase(font, text, opts)
+		if err != nil {
+			// Шрифт без нужных символов не мешает показать остальные
+			warn("%s: %v", font.name, err)
+			status = exitUnsupported
+			continue
+		}
+		entries = append(entries, entry)
+		opts.write.protected = append(opts.write.protected, font.path)
+	}
+
+	if len(entries) == 0 {
+		return status
+	}
+	if len(opts.outputs) == 0 {
+		opts.outputs = []destination{{path: stdoutPath}}
+	}
+	return max(status, writeDestinations(opts, func(dest destination) ([]byte, error) {
+		return formatShowcase(entries, dest, opts)
+	}))
+}
+
+// renderShowcase рисует текст шрифтом font с учётом --unsupported, переноса и концов строк
+func renderShowcase(font fontInfo, text string, opts options) (showcaseEntry, error) {
+	ascii := NewASCIIArt()
+	if err := ascii.LoadFont(font.path); err != nil {
+		return showcaseEntry{}, err
+	}
+	text, err := ascii.applyCharPolicy(text, opts.chars)
+	if err != nil {
+		return showcaseEntry{}, err
+	}
+	ascii.width = opts.wrapWidth()
+	art := applyLineEnds(ascii.RenderText(text), opts.ends)
+
+	entry := showcaseEntry{font: font, art: art}
+	for _, line := range strings.Split(strings.TrimSuffix(art, "\n"), "\n") {
+		entry.height++
+		entry.width = max(entry.width, displayWidth(line))
+	}
+	return entry, nil
+}
+
+// showcaseLabel — подпись образца: размеры шрифта на этом тексте
+func showcaseLabel(entry showcaseEntry) string {
+	return fmt.Sprintf(tr("height %d, width %d"), entry.height, entry.width)
+}
+
+// formatShowcase оформляет все образцы для места назначения: в HTML — одной
+// страницей с заголовком для каждого шрифта, иначе текстом с подписями
+func formatShowcase(entries []showcaseEntry, dest destination, opts options) ([]byte, error) {
+	if dest.format == "" {
+		dest.format = opts.format
+	}
+	var b strings.Builder
+	if dest.format == formatHTML {
+		fmt.Fprintf(&b, "<!DOCTYPE html>\n<html>\n<head><meta charset=\"utf-8\"><title>%s</title></head>\n<body>\n", html.EscapeString(tr("Font showcase")))
+		for _, entry := range entries {
+			fmt.Fprintf(&b, "<h2>%s</h2>\n<p>%s</p>\n", html.EscapeString(entry.font.name), showcaseLabel(entry))
+			if opts.color != "" {
+				fmt.Fprintf(&b, "<pre style=\"color: %s\">\n", opts.color)
+			} else {
+				b.WriteString("<pre>\n")
+			}
+			b.WriteString(html.EscapeString(entry.art))
+			b.WriteString("</pre>\n")
+		}
+		b.WriteString("</body>\n</html>\n")
+	} else {
+		for i, entry := range entries {
+			if i > 0 {
+				b.WriteString("\n")
+			}
+			fmt.Fprintf(&b, "%s (%s)\n", entry.font.name, showcaseLabel(entry))
+			b.WriteString(formatOutput(entry.art, dest.format, opts.color))
+		}
+	}
+
+	data := b.String()
+	// Обёртка (markdown или комментарии) действует только на текстовый вывод
+	if dest.format == formatPlain {
+		if dest.wrap == "" {
+			dest.wrap = opts.wrap
+		}
+		var err error
+		if data, err = wrapOutput(data, dest.wrap); err != nil {
+			return nil, err
+		}
+	}
+	if !dest.gzip {
+		return []byte(data), nil
+	}
+	return compressOutput([]byte(data), dest.path)
+}
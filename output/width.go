@@ -0,0 +1,86 @@
+package main
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// wideRanges — диапазоны восточноазиатских широких и полноширинных символов,
+// которые занимают в терминале две колонки
+var wideRanges = []struct{ lo, hi rune }{
+	{0x1100, 0x115F},   // Хангыль: начальные согласные
+	{0x2E80, 0x303E},   // Ключи CJK, знаки препинания CJK
+	{0x3041, 0x33FF},   // Хирагана, катакана, бопомофо, совместимость CJK
+	{0x3400, 0x4DBF},   // Иероглифы CJK, расширение A
+	{0x4E00, 0x9FFF},   // Унифицированные иероглифы CJK
+	{0xA000, 0xA4CF},   // Письменность и
+	{0xAC00, 0xD7A3},   // Слоги хангыля
+	{0xF900, 0xFAFF},   // Совместимые иероглифы CJK
+	{0xFE30, 0xFE4F},   // Совместимые формы CJK
+	{0xFF00, 0xFF60},   // Полноширинные формы
+	{0xFFE0, 0xFFE6},   // Полноширинные знаки
+	{0x1F300, 0x1F64F}, // Пиктограммы и эмодзи
+	{0x1F900, 0x1F9FF}, // Дополнительные пиктограммы
+	{0x20000, 0x2FFFD}, // Иероглифы CJK, расширения B–F
+	{0x30000, 0x3FFFD}, // Иероглифы CJK, расширение G
+}
+
+// runeWidth возвращает число колонок терминала, которое занимает руна
+func runeWidth(r rune) int {
+	switch {
+	case r < 0x20 || (r >= 0x7F && r < 0xA0):
+		return 0 // управляющие символы
+	case unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf):
+		return 0 // комбинируемые знаки и невидимые символы форматирования
+	}
+	for _, rng := range wideRanges {
+		if r >= rng.lo && r <= rng.hi {
+			return 2
+		}
+	}
+	return 1
+}
+
+// escapeLen возвращает длину escape-последовательности в начале s (0, если её нет).
+// Поддерживаются CSI (ESC [ ... буква), OSC (ESC ] ... BEL или ESC \) и двухбайтовые ESC X.
+func escapeLen(s string) int {
+	if len(s) < 2 || s[0] != '\033' {
+		return 0
+	}
+	switch s[1] {
+	case '[':
+		for i := 2; i < len(s); i++ {
+			if s[i] >= 0x40 && s[i] <= 0x7E {
+				return i + 1
+			}
+		}
+		return len(s)
+	case ']':
+		for i := 2; i < len(s); i++ {
+			if s[i] == '\a' {
+				return i + 1
+			}
+			if s[i] == '\033' && i+1 < len(s) && s[i+1] == '\\' {
+				return i + 2
+			}
+		}
+		return len(s)
+	}
+	return 2
+}
+
+// displayWidth возвращает ширину строки в колонках терминала: escape-последовательности
+// не занимают места, широкие символы занимают две колонки, комбинируемые — ни одной
+func displayWidth(s string) int {
+	width := 0
+	for i := 0; i < len(s); {
+		if n := escapeLen(s[i:]); n > 0 {
+			i += n
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		width += runeWidth(r)
+		i += size
+	}
+	return width
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"sort"
+	"strings"
+)
+
+// wrapMarkdown — обёртка в блок кода Markdown
+const wrapMarkdown = "markdown"
+
+// commentStyle описывает синтаксис комментариев языка:
+// либо строчный префикс, либо пару открывающего и закрывающего маркеров
+type commentStyle struct {
+	line   string
+	open   string
+	close  string
+	splice bool // \ в конце строки склеивает её со следующей (C, C++)
+}
+
+// commentStyles связывает названия обёрток (и сами маркеры) с синтаксисом комментариев
+var commentStyles = map[string]commentStyle{
+	"//":     {line: "//", splice: true},
+	"go":     {line: "//"},
+	"js":     {line: "//"},
+	"#":      {line: "#"},
+	"python": {line: "#"},
+	"sh":     {line: "#"},
+	"--":     {line: "--"},
+	"sql":    {line: "--"},
+	"/*":     {open: "/*", close: "*/"},
+	"c":      {open: "/*", close: "*/"},
+}
+
+// wrapByExt определяет обёртку по расширению файла
+var wrapByExt = map[string]string{
+	".md":  wrapMarkdown,
+	".go":  "go",
+	".js":  "js",
+	".py":  "python",
+	".sh":  "sh",
+	".sql": "sql",
+	".c":   "c",
+	".h":   "c",
+}
+
+// isValidWrap проверяет, известна ли обёртка с таким названием
+func isValidWrap(name string) bool {
+	_, ok := commentStyles[name]
+	return ok || name == wrapMarkdown
+}
+
+// wrapNames возвращает названия обёрток по алфавиту
+func wrapNames() []string {
+	names := []string{wrapMarkdown}
+	for name := range commentStyles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// wrapOutput оборачивает ASCII-арт в блок Markdown или комментарии выбранного языка.
+// Хвостовые пробелы в строках удаляются, чтобы линтеры и редакторы их не трогали.
+func wrapOutput(art, name string) (string, error) {
+	if name == "" || art == "" {
+		return art, nil
+	}
+	lines := strings.Split(strings.TrimSuffix(art, "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t")
+	}
+
+	var b strings.Builder
+	if name == wrapMarkdown {
+		// Забор должен быть длиннее любой серии обратных кавычек внутри арта
+		fence := strings.Repeat("`", max(3, longestRun(art, '`')+1))
+		b.WriteString(fence + "text\n")
+		for _, line := range lines {
+			b.WriteString(line + "\n")
+		}
+		b.WriteString(fence + "\n")
+		return b.String(), nil
+	}
+
+	style, ok := commentStyles[name]
+	if !ok {
+		return "", errorf("unknown wrapper %s", name)
+	}
+	if style.line != "" {
+		for _, line := range lines {
+			switch {
+			case line == "":
+				b.WriteString(style.line + "\n")
+			case style.splice && strings.HasSuffix(line, `\`):
+				// В C и C++ комментарий, который оканчивается на \, продолжается на
+				// следующей строке исходника (gcc склеивает строки и при пробелах
+				// после \), поэтому строку закрывает ещё один маркер
+				b.WriteString(style.line + " " + line + " " + style.line + "\n")
+			default:
+				b.WriteString(style.line + " " + line + "\n")
+			}
+		}
+		return b.String(), nil
+	}
+
+	// В блочном комментарии разрываем маркеры, которые закрыли бы
+	// комментарий раньше времени (или открыли вложенный в Rust/Swift)
+	b.WriteString(style.open + "\n")
+	for _, line := range lines {
+		b.WriteString(escapeBlockComment(line, style) + "\n")
+	}
+	b.WriteString(style.close + "\n")
+	return b.String(), nil
+}
+
+// escapeBlockComment вставляет пробел внутрь маркеров комментария в строке
+func escapeBlockComment(line string, style commentStyle) string {
+	for _, marker := range []string{style.close, style.open} {
+		broken := marker[:1] + " " + marker[1:]
+		for strings.Contains(line, marker) {
+			line = strings.ReplaceAll(line, marker, broken)
+		}
+	}
+	return line
+}
+
+// longestRun возвращает длину самой длинной серии символа r в строке
+func longestRun(s string, r rune) int {
+	longest, current := 0, 0
+	for _, c := range s {
+		if c == r {
+			current++
+			longest = max(longest, current)
+		} else {
+			current = 0
+		}
+	}
+	return longest
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestWrapOutput(t *testing.T) {
+	tests := []struct {
+		name, art, wrap, want string
+	}{
+		{"none", "a  \n", "", "a  \n"},
+		{"markdown", "a  \nb\n", wrapMarkdown, "```text\na\nb\n```\n"},
+		{"markdown with fence", "``` \n", wrapMarkdown, "````text\n```\n````\n"},
+		{"go", "a \n\n", "go", "// a\n//\n"},
+		{"go backslash", "a \\ \n", "go", "// a \\\n"},
+		{"c line backslash", "a \\ \n", "//", "// a \\ //\n"},
+		{"python", "a\n", "python", "# a\n"},
+		{"sql", "a\n", "sql", "-- a\n"},
+		{"c", "a */ b\n", "c", "/*\na * / b\n*/\n"},
+		{"c open marker", "/*/\n", "c", "/*\n/ * /\n*/\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := wrapOutput(tt.art, tt.wrap)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("wrapOutput(%q, %q) = %q, want %q", tt.art, tt.wrap, got, tt.want)
+			}
+		})
+	}
+}
+
+// renderForWrap рисует текст стандартным шрифтом, как его увидит обёртка
+func renderForWrap(t *testing.T, text string) string {
+	t.Helper()
+	ascii := NewASCIIArt()
+	if err := ascii.LoadFont("standard.txt"); err != nil {
+		t.Fatal(err)
+	}
+	return ascii.RenderText(text)
+}
+
+// wrapSamples — тексты, в арте которых есть маркеры комментариев и \ в конце строк
+var wrapSamples = []string{`\`, `/*\*/`, `V\/`, "*/ /* // --"}
+
+func TestWrapGoCompiles(t *testing.T) {
+	for _, text := range wrapSamples {
+		wrapped, err := wrapOutput(renderForWrap(t, text), "go")
+		if err != nil {
+			t.Fatal(err)
+		}
+		src := "package art\n\n" + wrapped + "var X = 1\n"
+		if _, err := parser.ParseFile(token.NewFileSet(), "art.go", src, parser.AllErrors); err != nil {
+			t.Errorf("wrapped %q does not parse: %v\n%s", text, err, src)
+		}
+	}
+}
+
+func TestWrapCCompiles(t *testing.T) {
+	gcc, err := exec.LookPath("gcc")
+	if err != nil {
+		t.Skip("gcc not found")
+	}
+	for _, wrap := range []string{"//", "c"} {
+		for _, text := range wrapSamples {
+			wrapped, err := wrapOutput(renderForWrap(t, text), wrap)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// Если комментарий закроется раньше времени, остаток арта станет
+			// кодом; склеенные \ строки gcc отмечает предупреждением -Wcomment
+			src := wrapped + "int x;\n" + wrapped + "int f(void) { return x; }\n"
+			path := filepath.Join(t.TempDir(), "art.c")
+			if err := os.WriteFile(path, []byte(src), 0644); err != nil {
+				t.Fatal(err)
+			}
+			if out, err := exec.Command(gcc, "-fsyntax-only", "-Wall", "-Werror", path).CombinedOutput(); err != nil {
+				t.Errorf("%s: wrapped %q does not compile: %v\n%s\n%s", wrap, text, err, out, src)
+			}
+		}
+	}
+}
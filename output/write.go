@@ -0,0 +1,129 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// errSkipped сообщает, что файл уже существует и был пропущен из-за --no-clobber
+var errSkipped = message("file exists, skipped (--no-clobber)")
+
+// writeOptions описывает политику записи результата в файл
+type writeOptions struct {
+	force     bool     // перезаписывать существующий файл
+	noClobber bool     // молча пропускать существующий файл
+	append    bool     // дописывать в конец файла вместо перезаписи
+	mkdirs    bool     // создавать недостающие родительские каталоги
+	protected []string // файлы, которые нельзя перезаписывать (загруженные шрифты)
+}
+
+// validateOutputPath проверяет, что путь пригоден для записи результата
+func validateOutputPath(path string) error {
+	if strings.TrimSpace(path) == "" {
+		return errorf("empty output path")
+	}
+	if strings.HasSuffix(path, "/") || strings.HasSuffix(path, string(filepath.Separator)) {
+		return errorf("output path %s is a directory", path)
+	}
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		return errorf("output path %s is a directory", path)
+	}
+	return nil
+}
+
+// isProtected проверяет, указывает ли путь на один из защищённых файлов
+func isProtected(path string, protected []string) bool {
+	target, err := os.Stat(path)
+	if err != nil {
+		return false // файла ещё нет, значит он не может быть шрифтом
+	}
+	for _, p := range protected {
+		if info, err := os.Stat(p); err == nil && os.SameFile(target, info) {
+			return true
+		}
+	}
+	return false
+}
+
+// writeOutput записывает данные в файл согласно политике opts.
+// Обычная запись атомарна: данные пишутся во временный файл в том же каталоге,
+// который затем переименовывается в целевой, так что при ошибке старый файл не портится.
+func writeOutput(path string, data []byte, opts writeOptions) error {
+	if err := validateOutputPath(path); err != nil {
+		return err
+	}
+	if isProtected(path, opts.protected) {
+		return errorf("refusing to overwrite font file %s", path)
+	}
+
+	dir := filepath.Dir(path)
+	if opts.mkdirs {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return errorf("failed to create directory %s: %v", dir, err)
+		}
+	} else if _, err := os.Stat(dir); err != nil {
+		return errorf("directory %s does not exist (use --mkdir to create it)", dir)
+	}
+
+	if opts.append {
+		return appendFile(path, data)
+	}
+
+	if _, err := os.Stat(path); err == nil {
+		switch {
+		case opts.noClobber:
+			return errSkipped
+		case !opts.force:
+			return errorf("file %s already exists (use --force to overwrite)", path)
+		}
+	}
+	return writeAtomic(path, data)
+}
+
+// appendFile дописывает данные в конец файла, создавая его при необходимости
+func appendFile(path string, data []byte) error {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return errorf("failed to open %s: %v", path, err)
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return errorf("failed to write %s: %v", path, err)
+	}
+	return file.Close()
+}
+
+// writeAtomic пишет данные во временный файл и переименовывает его в path
+func writeAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return errorf("failed to create temporary file: %v", err)
+	}
+	tmpName := tmp.Name()
+	// Удаляем временный файл, если что-то пошло не так
+	cleanup := func(err error) error {
+		tmp.Close()
+		os.Remove(tmpName)
+		return err
+	}
+
+	if _, err := tmp.Write(data); err != nil {
+		return cleanup(errorf("failed to write %s: %v", tmpName, err))
+	}
+	if err := tmp.Sync(); err != nil {
+		return cleanup(errorf("failed to sync %s: %v", tmpName, err))
+	}
+	if err := tmp.Chmod(0644); err != nil {
+		return cleanup(errorf("failed to chmod %s: %v", tmpName, err))
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpName)
+		return errorf("failed to close %s: %v", tmpName, err)
+	}
+	if err := os.Rename(tmpName, path); err != nil {
+		os.Remove(tmpName)
+		return errorf("failed to rename %s to %s: %v", tmpName, path, err)
+	}
+	return nil
+}
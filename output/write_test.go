@@ -0,0 +1,78 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteOutput(t *testing.T) {
+	tests := []struct {
+		name, path string
+		opts       writeOptions
+		wantErr    error // errSkipped, или любая ошибка при errAny
+		want       string
+	}{
+		{"new file", "new.txt", writeOptions{}, nil, "art"},
+		{"existing file", "old.txt", writeOptions{}, errAny, "old"},
+		{"force", "old.txt", writeOptions{force: true}, nil, "art"},
+		{"no clobber", "old.txt", writeOptions{noClobber: true}, errSkipped, "old"},
+		{"no clobber new file", "new.txt", writeOptions{noClobber: true}, nil, "art"},
+		{"append", "old.txt", writeOptions{append: true}, nil, "oldart"},
+		{"append new file", "new.txt", writeOptions{append: true}, nil, "art"},
+		{"font", "font.txt", writeOptions{force: true}, errAny, "font"},
+		{"font append", "font.txt", writeOptions{append: true}, errAny, "font"},
+		{"font hard link", "link.txt", writeOptions{force: true}, errAny, "font"},
+		{"missing directory", "missing/new.txt", writeOptions{}, errAny, ""},
+		{"mkdir", "sub/dir/new.txt", writeOptions{mkdirs: true}, nil, "art"},
+		{"directory", "sub", writeOptions{force: true}, errAny, ""},
+		{"trailing slash", "new/", writeOptions{mkdirs: true}, errAny, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			font := filepath.Join(dir, "font.txt")
+			mustWrite(t, font, "font")
+			mustWrite(t, filepath.Join(dir, "old.txt"), "old")
+			if err := os.Link(font, filepath.Join(dir, "link.txt")); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.Mkdir(filepath.Join(dir, "sub"), 0755); err != nil {
+				t.Fatal(err)
+			}
+			tt.opts.protected = []string{font}
+
+			path := dir + string(filepath.Separator) + tt.path
+			err := writeOutput(path, []byte("art"), tt.opts)
+			switch {
+			case tt.wantErr == nil && err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			case tt.wantErr == errAny && err == nil:
+				t.Fatal("no error")
+			case tt.wantErr == errSkipped && !errors.Is(err, errSkipped):
+				t.Fatalf("got %v, want %v", err, errSkipped)
+			}
+			if tt.want != "" {
+				if data, err := os.ReadFile(path); err != nil || string(data) != tt.want {
+					t.Errorf("file holds %q (%v), want %q", data, err, tt.want)
+				}
+			}
+			// После записи не должно оставаться временных файлов
+			leftovers, _ := filepath.Glob(filepath.Join(filepath.Dir(path), ".*.tmp-*"))
+			if len(leftovers) > 0 {
+				t.Errorf("temporary files left behind: %v", leftovers)
+			}
+		})
+	}
+}
+
+// errAny в таблице означает, что подходит любая ошибка
+var errAny = errors.New("any error")
+
+func mustWrite(t *testing.T, path, data string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+}